@@ -8,12 +8,15 @@
 package parser
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/tidwall/gjson"
@@ -26,86 +29,195 @@ type IOSIAP struct {
 
 // IOS市场
 type IOSData struct {
-	IOSID               string    `bson:"ios_id"`                 // ios id
-	IOSFullName         string    `bson:"ios_full_name"`          // 应用名称(会包含 - 后面的内容)
-	IOSName             string    `bson:"ios_name"`               // 应用名称
-	IOSIcon             string    `bson:"ios_icon"`               // ios 图标地址
-	IOSBundleID         string    `bson:"ios_bundle_id"`          // ios bundle id
-	IOSDesc             string    `bson:"ios_desc"`               // ios 描述
-	IOSPrivacyPolicyUrl string    `bson:"ios_privacy_policy_url"` // ios 隐私政策地址
-	IOSOtherApps        []*App    `bson:"ios_other_apps"`         // ios 全部同主体的app
-	IOSCategory         string    `bson:"ios_category"`           // ios 分类
-	IOSPackageSize      string    `bson:"ios_package_size"`       // ios 包大小
-	IOSLanguage         string    `bson:"ios_language"`           // ios 支持语言
-	IOSSupplier         string    `bson:"ios_supplier"`           // ios 供应商名称
-	IOSRate             string    `bson:"ios_rate"`               // ios 评分
-	IOSRateCount        string    `bson:"ios_rate_count"`         // ios 评价数
-	IOSLastVersion      string    `bson:"ios_last_version"`       // ios 最新版本
-	IOSLastUpdate       string    `bson:"ios_last_update"`        // ios 最新版本时间
-	IOSIAPList          []*IOSIAP `bson:"ios_ipa_list"`           // ios 内购列表
+	IOSID               string              `bson:"ios_id"`                 // ios id
+	IOSCountry          string              `bson:"ios_country"`            // ios 区域
+	IOSFullName         string              `bson:"ios_full_name"`          // 应用名称(会包含 - 后面的内容)
+	IOSName             string              `bson:"ios_name"`               // 应用名称
+	IOSIcon             string              `bson:"ios_icon"`               // ios 图标地址
+	IOSBundleID         string              `bson:"ios_bundle_id"`          // ios bundle id
+	IOSDesc             string              `bson:"ios_desc"`               // ios 描述
+	IOSPrivacyPolicyUrl string              `bson:"ios_privacy_policy_url"` // ios 隐私政策地址
+	IOSOtherApps        []*App              `bson:"ios_other_apps"`         // ios 全部同主体的app
+	IOSCategory         string              `bson:"ios_category"`           // ios 分类
+	IOSPackageSize      string              `bson:"ios_package_size"`       // ios 包大小
+	IOSLanguage         string              `bson:"ios_language"`           // ios 支持语言
+	IOSSupplier         string              `bson:"ios_supplier"`           // ios 供应商名称
+	IOSRate             string              `bson:"ios_rate"`               // ios 评分
+	IOSRateCount        string              `bson:"ios_rate_count"`         // ios 评价数
+	IOSLastVersion      string              `bson:"ios_last_version"`       // ios 最新版本
+	IOSLastUpdate       string              `bson:"ios_last_update"`        // ios 最新版本时间
+	IOSIAPList          []*IOSIAP           `bson:"ios_ipa_list"`           // ios 内购列表
+	IOSKind             string              `bson:"ios_kind"`               // ios 应用类型，software 为 iOS 应用，mac-software 为 mac 应用
+	IOSPlatforms        []string            `bson:"ios_platforms"`          // ios 支持的平台，例：iphone、ipad、mac
+	IOSCompatibility    []*IOSCompatibility `bson:"ios_compatibility"`      // ios 各平台的兼容性要求与最低系统版本
+	IOSSupportedDevices []string            `bson:"ios_supported_devices"`  // ios 支持的设备型号，来自 lookup 接口，mac 应用为空
+	IOSMedia            Media               `bson:"ios_media"`              // ios 截图与预览视频
+	IOSPrivacyLabels    []*IOSPrivacyLabel  `bson:"ios_privacy_labels"`     // ios 隐私标签
+	IOSVersionHistory   []VersionEntry      `bson:"ios_version_history"`    // ios 全部版本历史，从新到旧
+
+	IOSRateValue       float64         `bson:"ios_rate_value"`       // ios 评分，数字
+	IOSRateCountValue  int64           `bson:"ios_rate_count_value"` // ios 评价数，数字
+	IOSPackageBytes    int64           `bson:"ios_package_bytes"`    // ios 包大小，字节数
+	IOSLastUpdateTime  time.Time       `bson:"ios_last_update_time"` // ios 最新版本时间
+	IOSRatingHistogram RatingHistogram `bson:"ios_rating_histogram"` // ios 各星级的评分数，页面上只有比例，按评价数估算
+}
+
+// 获取ios数据，opts 可以指定区域与语言，不传时使用中国区
+func ParseIOSData(iosId string, opts ...*IOSOptions) (*IOSData, error) {
+	return ParseIOSDataContext(context.Background(), iosId, opts...)
+}
+
+// 获取ios数据，ctx 取消或超时时中断请求
+func ParseIOSDataContext(ctx context.Context, iosId string, opts ...*IOSOptions) (*IOSData, error) {
+	return DefaultClient.ParseIOSData(ctx, iosId, opts...)
 }
 
 // 获取ios数据
-func ParseIOSData(iosId string) (*IOSData, error) {
+func (c *Client) ParseIOSData(ctx context.Context, iosId string, opts ...*IOSOptions) (*IOSData, error) {
+	opt := firstIOSOptions(opts)
+
 	if strings.TrimSpace(iosId) == "" {
 		return nil, errors.New("iosId 不能为空")
 	}
 
-	appStoreDoc, err := getAppStoreDoc(iosId)
-	if err != nil {
-		return nil, err
+	var (
+		iosData *IOSData
+		err     error
+	)
+
+	if opt.source() == IOSSourceAPI {
+		iosData, err = c.parseIOSDataFromAPI(ctx, iosId, opt)
+	} else {
+		iosData, err = c.parseIOSDataFromHTML(ctx, iosId, opt)
 	}
 
-	appStoreOtherAppsDoc, err := getAppStoreOtherAppsDoc(iosId)
 	if err != nil {
 		return nil, err
 	}
 
+	// 数字与时间
+	iosData.IOSRateValue = normalizeRate(iosData.IOSRate)
+	iosData.IOSRateCountValue = normalizeCount(iosData.IOSRateCount)
+	iosData.IOSPackageBytes = normalizeSize(iosData.IOSPackageSize)
+	iosData.IOSLastUpdateTime = normalizeIOSDate(iosData.IOSLastUpdate, opt)
+
+	// 平台
+	iosData.IOSPlatforms = getIOSPlatforms(iosData.IOSKind, iosData.IOSCompatibility, iosData.IOSSupportedDevices)
+
+	return iosData, nil
+}
+
+// 从详情页面获取ios数据
+func (c *Client) parseIOSDataFromHTML(ctx context.Context, iosId string, opt *IOSOptions) (*IOSData, error) {
+	var (
+		appStoreDoc          *goquery.Document
+		appStoreOtherAppsDoc *goquery.Document
+		docErr, otherAppsErr error
+		lookup               *gjson.Result
+	)
+
+	// 详情页、更多 app 页面与 lookup 接口互不依赖，同时请求
+	// lookup 接口只用来补充 bundle id、类型与设备，失败时这些字段留空，不影响页面数据
+	c.parallel(
+		func() { appStoreDoc, docErr = c.getAppStoreDoc(ctx, iosId, opt) },
+		func() { appStoreOtherAppsDoc, otherAppsErr = c.getAppStoreOtherAppsDoc(ctx, iosId, opt) },
+		func() { lookup, _ = c.getAppStoreLookup(ctx, iosId, opt) },
+	)
+
+	if docErr != nil {
+		return nil, docErr
+	}
+
+	if otherAppsErr != nil {
+		return nil, otherAppsErr
+	}
+
 	// 创建 ios data 结构体
 	iosData := new(IOSData)
 	iosData.IOSID = iosId
+	iosData.IOSCountry = opt.country()
 
 	// ios数据
 	iosData.IOSFullName = getAppStoreName(appStoreDoc)
+	if iosData.IOSFullName == "" {
+		return nil, layoutError("应用名称")
+	}
 	iosData.IOSName = getAppStoreCleanName(iosData.IOSFullName)
 	iosData.IOSIcon = getAppStoreIcon(appStoreDoc)
-	iosData.IOSBundleID = getAppStoreBundleID(iosId)
-	iosData.IOSSupplier = getAppStoreSupplier(appStoreDoc)
-	iosData.IOSCategory = getAppStoreCategory(appStoreDoc)
+	iosData.IOSBundleID = getAppStoreLookupString(lookup, "bundleId")
+	iosData.IOSSupplier = getAppStoreSupplier(appStoreDoc, opt)
+	iosData.IOSCategory = getAppStoreCategory(appStoreDoc, opt)
 	iosData.IOSDesc = getAppStoreDesc(appStoreDoc)
-	iosData.IOSLanguage = getAppStoreLanguage(appStoreDoc)
-	iosData.IOSRate = getAppStoreRate(appStoreDoc)
-	iosData.IOSRateCount = getAppStoreRateCount(appStoreDoc)
-	iosData.IOSPackageSize = getAppStorePackageSize(appStoreDoc)
-	iosData.IOSPrivacyPolicyUrl = getAppStorePrivacyPolicyUrl(appStoreDoc)
+	iosData.IOSLanguage = getAppStoreLanguage(appStoreDoc, opt)
+	iosData.IOSRate = getAppStoreRate(appStoreDoc, opt)
+	iosData.IOSRateCount = getAppStoreRateCount(appStoreDoc, opt)
+	iosData.IOSRatingHistogram = getAppStoreRatingHistogram(appStoreDoc, opt, normalizeCount(iosData.IOSRateCount))
+	iosData.IOSPackageSize = getAppStorePackageSize(appStoreDoc, opt)
+	iosData.IOSPrivacyPolicyUrl = getAppStorePrivacyPolicyUrl(appStoreDoc, opt)
 	iosData.IOSOtherApps = getAppStoreDeveloperOtherApps(appStoreOtherAppsDoc)
-	iosData.IOSIAPList = getAppStoreIAPList(appStoreDoc)
-	lastVersion, lastUpdate := getAppStoreLastUpdate(appStoreDoc)
+	iosData.IOSIAPList = getAppStoreIAPList(appStoreDoc, opt)
+	lastVersion, lastUpdate := getAppStoreLastUpdate(appStoreDoc, opt)
 	iosData.IOSLastVersion = lastVersion
 	iosData.IOSLastUpdate = lastUpdate
+	iosData.IOSKind = getAppStoreLookupString(lookup, "kind")
+	iosData.IOSSupportedDevices = getAppStoreLookupDevices(lookup)
+	iosData.IOSCompatibility = getAppStoreCompatibility(appStoreDoc, opt)
+	iosData.IOSMedia = getAppStoreMedia(appStoreDoc, opt)
+	iosData.IOSPrivacyLabels = getAppStorePrivacyLabels(appStoreDoc, opt)
+	iosData.IOSVersionHistory = getAppStoreVersionHistory(appStoreDoc)
 
 	return iosData, nil
 }
 
+// app store 市场
+type iosStore struct {
+	opts *IOSOptions
+}
+
+// 创建指定区域与语言的 app store 市场，可以用 RegisterStore 替换默认的中国区
+func NewIOSStore(opts *IOSOptions) Store {
+	return iosStore{opts: opts}
+}
+
+func (iosStore) ID() string {
+	return StoreIOS
+}
+
+func (s iosStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	return c.ParseIOSData(ctx, id, s.opts)
+}
+
+func (s iosStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	return c.getAppStoreSearchID(ctx, name, s.opts)
+}
+
+func (s iosStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	json, err := c.getAppStoreLookup(ctx, id, s.opts)
+	if err != nil {
+		return false, err
+	}
+
+	return json.Get("resultCount").Int() > 0, nil
+}
+
 // app store 的 doc 内容
-func getAppStoreDoc(id string) (*goquery.Document, error) {
-	url := "https://apps.apple.com/cn/app/id" + id + "/"
-	client := &http.Client{}
+func (c *Client) getAppStoreDoc(ctx context.Context, id string, opts *IOSOptions) (*goquery.Document, error) {
+	u := opts.appURL(id, nil)
 
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("返回状态错误 %v", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Load the HTML document
@@ -118,24 +230,23 @@ func getAppStoreDoc(id string) (*goquery.Document, error) {
 }
 
 // app store 更多此开发人员的 app 页面的 doc
-func getAppStoreOtherAppsDoc(id string) (*goquery.Document, error) {
-	url := "https://apps.apple.com/cn/app/id" + id + "?see-all=developer-other-apps"
-	client := &http.Client{}
+func (c *Client) getAppStoreOtherAppsDoc(ctx context.Context, id string, opts *IOSOptions) (*goquery.Document, error) {
+	u := opts.appURL(id, url.Values{"see-all": {"developer-other-apps"}})
 
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("返回状态错误 %v", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Load the HTML document
@@ -148,15 +259,34 @@ func getAppStoreOtherAppsDoc(id string) (*goquery.Document, error) {
 }
 
 // 获取 section，app store 详情页面划分了 n 块 section 放不同内容，没有特定样式名，所以用标题匹配得到不同的 secion 块
-func getAppStoreSection(doc *goquery.Document, title string) *goquery.Selection {
+func getAppStoreSection(doc *goquery.Document, titles []string) *goquery.Selection {
 	content := doc.Find("section.section")
 
 	return content.FilterFunction(func(i int, s *goquery.Selection) bool {
 		h := s.Find(".section__headline").Text()
-		return strings.HasPrefix(strings.TrimSpace(h), title)
+		_, ok := trimAnyPrefix(strings.TrimSpace(h), titles)
+		return ok
 	})
 }
 
+// 获取信息块中某一项的内容，labels 为该项的标题
+func getAppStoreInfoItem(doc *goquery.Document, opts *IOSOptions, labels []string) string {
+	value := getAppStoreInfoItemSelection(doc, opts, labels).Find("dd").Text()
+	return strings.TrimSpace(value)
+}
+
+// 获取信息块中的某一项，labels 为该项的标题，找不到时返回空的 selection
+func getAppStoreInfoItemSelection(doc *goquery.Document, opts *IOSOptions, labels []string) *goquery.Selection {
+	sel := getAppStoreSection(doc, opts.locale().Information)
+
+	items := sel.Find(".information-list .information-list__item")
+	return items.FilterFunction(func(i int, s *goquery.Selection) bool {
+		term := strings.TrimSpace(s.Find("dt").First().Text())
+		_, ok := trimAnyPrefix(term, labels)
+		return ok
+	}).First()
+}
+
 // 获取应用名称
 func getAppStoreName(doc *goquery.Document) string {
 	content := doc.Find("meta[property='og:title']")
@@ -208,32 +338,87 @@ func getAppStoreIcon(doc *goquery.Document) string {
 	return strings.TrimSpace(icon)
 }
 
-// 获取bundle id
-func getAppStoreBundleID(appid string) string {
-	u := "https://itunes.apple.com/lookup?id=" + appid
-	client := &http.Client{}
+// itunes lookup 接口的数据
+func (c *Client) getAppStoreLookup(ctx context.Context, appid string, opts *IOSOptions) (*gjson.Result, error) {
+	u := "https://itunes.apple.com/lookup?id=" + appid + "&country=" + opts.country()
 
-	request, err := http.NewRequest("GET", u, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
-		return ""
+		return nil, err
 	}
 
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
-		return ""
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
 	bytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return ""
+		return nil, err
 	}
 
 	json := gjson.ParseBytes(bytes)
-	id := json.Get("results.0.bundleId")
+	return &json, nil
+}
+
+// 根据应用名称搜索 ios id
+func (c *Client) getAppStoreSearchID(ctx context.Context, name string, opts *IOSOptions) (string, error) {
+	params := url.Values{}
+	params.Add("term", name)
+	params.Add("country", opts.country())
+	params.Add("entity", opts.searchEntity())
+	params.Add("limit", "1")
 
-	return id.String()
+	u := "https://itunes.apple.com/search?" + params.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return "", err
+	}
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	json := gjson.ParseBytes(bytes)
+	return json.Get("results.0.trackId").String(), nil
+}
+
+// 获取bundle id
+func (c *Client) getAppStoreBundleID(ctx context.Context, appid string, opts *IOSOptions) string {
+	json, err := c.getAppStoreLookup(ctx, appid, opts)
+	if err != nil {
+		return ""
+	}
+
+	return getAppStoreLookupString(json, "bundleId")
+}
+
+// 获取 lookup 接口中第一个结果的字段，接口失败时返回空
+func getAppStoreLookupString(json *gjson.Result, key string) string {
+	if json == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(json.Get("results.0." + key).String())
 }
 
 // 获取描述文案
@@ -244,90 +429,105 @@ func getAppStoreDesc(doc *goquery.Document) string {
 }
 
 // 获取供应商信息
-func getAppStoreSupplier(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "信息")
-
-	items := sel.Find(".information-list .information-list__item")
-	return strings.TrimSpace(items.Eq(0).Find("dd").Text())
+func getAppStoreSupplier(doc *goquery.Document, opts *IOSOptions) string {
+	return getAppStoreInfoItem(doc, opts, opts.locale().Seller)
 }
 
 // 获取分类
-func getAppStoreCategory(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "信息")
-
-	category := ""
-	items := sel.Find(".information-list .information-list__item")
-	items.Map(func(i int, s *goquery.Selection) string {
-		text := strings.TrimSpace(s.Text())
-		if strings.HasPrefix(text, "类別") {
-			category = strings.ReplaceAll(text, "类別", "")
-		}
-		return ""
-	})
-
-	return strings.TrimSpace(category)
+func getAppStoreCategory(doc *goquery.Document, opts *IOSOptions) string {
+	return getAppStoreInfoItem(doc, opts, opts.locale().Category)
 }
 
 // 获取语言
-func getAppStoreLanguage(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "信息")
-
-	language := ""
-	items := sel.Find(".information-list .information-list__item")
-	items.Map(func(i int, s *goquery.Selection) string {
-		text := strings.TrimSpace(s.Text())
-		if strings.HasPrefix(text, "语言") {
-			language = strings.ReplaceAll(text, "语言", "")
-		}
-		return ""
-	})
-
-	return strings.TrimSpace(language)
+func getAppStoreLanguage(doc *goquery.Document, opts *IOSOptions) string {
+	return getAppStoreInfoItem(doc, opts, opts.locale().Languages)
 }
 
 // 获取评分
-func getAppStoreRate(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "评分及评论")
+func getAppStoreRate(doc *goquery.Document, opts *IOSOptions) string {
+	sel := getAppStoreSection(doc, opts.locale().Ratings)
 
 	return sel.Find(".we-customer-ratings__averages .we-customer-ratings__averages__display").Text()
 }
 
 // 获取评价数量
-func getAppStoreRateCount(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "评分及评论")
+func getAppStoreRateCount(doc *goquery.Document, opts *IOSOptions) string {
+	sel := getAppStoreSection(doc, opts.locale().Ratings)
 
 	count := sel.Find(".we-customer-ratings__stats .we-customer-ratings__count").Text()
-	count = strings.ReplaceAll(count, "个评分", "")
+	for _, s := range opts.locale().RatingCount {
+		count = strings.ReplaceAll(count, s, "")
+	}
 
 	return strings.TrimSpace(count)
 }
 
+// 评分条形图每一行的星级，例：we-star-bar-graph__stars--5
+var appStoreStarsReg = regexp.MustCompile(`we-star-bar-graph__stars--([1-5])`)
+
+// 评分条形图的宽度，例：width: 77%;
+var appStoreBarWidthReg = regexp.MustCompile(`width:\s*([0-9.]+)%`)
+
+// 获取各星级的评分数，页面上的条形图只有比例，用评价总数 total 换算
+func getAppStoreRatingHistogram(doc *goquery.Document, opts *IOSOptions, total int64) RatingHistogram {
+	var h RatingHistogram
+
+	sel := getAppStoreSection(doc, opts.locale().Ratings)
+	rows := sel.Find(".we-customer-ratings__stats .we-star-bar-graph__row")
+
+	rows.Each(func(i int, s *goquery.Selection) {
+		// 没有星级样式时按顺序，第一行是 5 星
+		star := 5 - i
+		if m := appStoreStarsReg.FindStringSubmatch(s.Find(".we-star-bar-graph__stars").AttrOr("class", "")); m != nil {
+			star = int(m[1][0] - '0')
+		}
+
+		style := s.Find(".we-star-bar-graph__bar__foreground-bar").AttrOr("style", "")
+		m := appStoreBarWidthReg.FindStringSubmatch(style)
+		if m == nil {
+			return
+		}
+
+		percent, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return
+		}
+
+		h.set(star, int64(float64(total)*percent/100+0.5))
+	})
+
+	return h
+}
+
+// 日期，中国区页面上的时间按北京时间处理，其他区域按 UTC
+func normalizeIOSDate(s string, opts *IOSOptions) time.Time {
+	if opts.country() == defaultAppStoreCountry {
+		return normalizeLocalDate(s)
+	}
+	return normalizeDate(s)
+}
+
 // 获取最新版本号与时间，version update
-func getAppStoreLastUpdate(doc *goquery.Document) (string, string) {
+func getAppStoreLastUpdate(doc *goquery.Document, opts *IOSOptions) (string, string) {
 	content := doc.Find("section.whats-new")
 
 	version := content.Find(".whats-new__latest__version").Text()
-	version = strings.ReplaceAll(version, "版本", "")
+	version, _ = trimAnyPrefix(strings.TrimSpace(version), opts.locale().Version)
 
+	// 保留页面上的原文，例：2023年7月3日、Jul 3, 2023，由 normalizeDate 转成时间
 	update := content.Find("time").Text()
-	update = strings.ReplaceAll(update, "年", "-")
-	update = strings.ReplaceAll(update, "月", "-")
-	update = strings.ReplaceAll(update, "日", "")
 
 	return strings.TrimSpace(version), strings.TrimSpace(update)
 }
 
 // 获取包大小
-func getAppStorePackageSize(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "信息")
-
-	items := sel.Find(".information-list .information-list__item")
-	return strings.TrimSpace(items.Eq(1).Find("dd").Text())
+func getAppStorePackageSize(doc *goquery.Document, opts *IOSOptions) string {
+	return getAppStoreInfoItem(doc, opts, opts.locale().Size)
 }
 
 // 获取隐私政策链接地址
-func getAppStorePrivacyPolicyUrl(doc *goquery.Document) string {
-	sel := getAppStoreSection(doc, "信息")
+func getAppStorePrivacyPolicyUrl(doc *goquery.Document, opts *IOSOptions) string {
+	sel := getAppStoreSection(doc, opts.locale().Information)
 	items := sel.Find(".inline-list--app-extensions .inline-list__item")
 	len := items.Length()
 	return sel.Find(".inline-list--app-extensions .inline-list__item").Eq(len-1).Find("a").AttrOr("href", "")
@@ -384,8 +584,8 @@ func getAppStoreDeveloperOtherApps(doc *goquery.Document) []*App {
 }
 
 // 获取内购信息
-func getAppStoreIAPList(doc *goquery.Document) []*IOSIAP {
-	sel := getAppStoreSection(doc, "信息")
+func getAppStoreIAPList(doc *goquery.Document, opts *IOSOptions) []*IOSIAP {
+	sel := getAppStoreSection(doc, opts.locale().Information)
 
 	items := sel.Find(".list-with-numbers__item")
 	if items.Length() > 0 {
@@ -0,0 +1,183 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-18 14:02:37
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-18 14:02:37
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// 从 itunes lookup 接口获取ios数据，接口没有的内购、隐私政策与标签、评分分布、预览视频与版本历史从详情页面获取
+// 详情页面只是补充，请求失败时这些字段留空，不影响接口数据
+func (c *Client) parseIOSDataFromAPI(ctx context.Context, iosId string, opt *IOSOptions) (*IOSData, error) {
+	var (
+		lookup, developerApps *gjson.Result
+		appStoreDoc           *goquery.Document
+		lookupErr, docErr     error
+	)
+
+	// 开发者的其他应用要用 lookup 结果中的 artistId，和详情页面同时请求
+	c.parallel(
+		func() {
+			lookup, lookupErr = c.getAppStoreLookup(ctx, iosId, opt)
+			if lookupErr != nil {
+				return
+			}
+
+			// 同开发者的其他应用，接口失败时留空
+			if artistId := lookup.Get("results.0.artistId").String(); artistId != "" {
+				developerApps, _ = c.getAppStoreDeveloperLookup(ctx, artistId, opt)
+			}
+		},
+		func() { appStoreDoc, docErr = c.getAppStoreDoc(ctx, iosId, opt) },
+	)
+
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	if docErr != nil {
+		appStoreDoc = nil
+	}
+
+	if lookup.Get("resultCount").Int() == 0 {
+		return nil, ErrNotFound
+	}
+
+	json := lookup.Get("results.0")
+
+	// 创建 ios data 结构体
+	iosData := new(IOSData)
+	iosData.IOSID = iosId
+	iosData.IOSCountry = opt.country()
+
+	// 接口数据
+	iosData.IOSFullName = getAppStoreAPIString(&json, "trackName")
+	if iosData.IOSFullName == "" {
+		return nil, layoutError("trackName")
+	}
+	iosData.IOSName = getAppStoreCleanName(iosData.IOSFullName)
+	iosData.IOSIcon = getAppStoreAPIString(&json, "artworkUrl512")
+	iosData.IOSBundleID = getAppStoreAPIString(&json, "bundleId")
+	iosData.IOSSupplier = getAppStoreAPIString(&json, "sellerName")
+	iosData.IOSCategory = getAppStoreAPIString(&json, "primaryGenreName")
+	iosData.IOSDesc = getAppStoreAPIString(&json, "description")
+	iosData.IOSLanguage = getAppStoreAPILanguage(&json)
+	iosData.IOSRate = getAppStoreAPIString(&json, "averageUserRating")
+	iosData.IOSRateCount = getAppStoreAPIString(&json, "userRatingCount")
+	iosData.IOSPackageSize = getAppStoreAPIString(&json, "fileSizeBytes")
+	iosData.IOSLastVersion = getAppStoreAPIString(&json, "version")
+	iosData.IOSLastUpdate = getAppStoreAPIString(&json, "currentVersionReleaseDate")
+	iosData.IOSKind = getAppStoreAPIString(&json, "kind")
+	iosData.IOSSupportedDevices = getAppStoreLookupDevices(lookup)
+
+	if developerApps != nil {
+		iosData.IOSOtherApps = getAppStoreAPIDeveloperOtherApps(developerApps, iosId)
+	}
+
+	// 接口没有的字段
+	if appStoreDoc != nil {
+		iosData.IOSPrivacyPolicyUrl = getAppStorePrivacyPolicyUrl(appStoreDoc, opt)
+		iosData.IOSIAPList = getAppStoreIAPList(appStoreDoc, opt)
+		iosData.IOSPrivacyLabels = getAppStorePrivacyLabels(appStoreDoc, opt)
+		iosData.IOSVersionHistory = getAppStoreVersionHistory(appStoreDoc)
+		iosData.IOSRatingHistogram = getAppStoreRatingHistogram(appStoreDoc, opt, normalizeCount(iosData.IOSRateCount))
+		iosData.IOSCompatibility = getAppStoreCompatibility(appStoreDoc, opt)
+		iosData.IOSMedia = getAppStoreMedia(appStoreDoc, opt)
+	}
+
+	// 页面上没有兼容性信息时，用接口的最低系统版本
+	if len(iosData.IOSCompatibility) == 0 {
+		iosData.IOSCompatibility = getAppStoreAPICompatibility(&json)
+	}
+
+	// 页面上的截图可以选尺寸，也有预览视频，取不到时用接口的截图
+	if len(iosData.IOSMedia.Screenshots) == 0 {
+		iosData.IOSMedia = getAppStoreAPIMedia(&json)
+	}
+
+	return iosData, nil
+}
+
+// itunes lookup 接口查询开发者的全部应用
+func (c *Client) getAppStoreDeveloperLookup(ctx context.Context, artistId string, opts *IOSOptions) (*gjson.Result, error) {
+	u := "https://itunes.apple.com/lookup?id=" + artistId + "&entity=" + opts.searchEntity() + "&country=" + opts.country()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	json := gjson.ParseBytes(bytes)
+	return &json, nil
+}
+
+// 获取接口中的字段
+func getAppStoreAPIString(json *gjson.Result, key string) string {
+	return strings.TrimSpace(json.Get(key).String())
+}
+
+// 获取支持语言，接口返回的是语言代码，例：ZH, EN
+func getAppStoreAPILanguage(json *gjson.Result) string {
+	list := make([]string, 0)
+
+	json.Get("languageCodesISO2A").ForEach(func(_, value gjson.Result) bool {
+		list = append(list, value.String())
+		return true
+	})
+
+	return strings.Join(list, ", ")
+}
+
+// 获取同开发者的其他应用，第一条是开发者信息，跳过开发者与应用本身
+func getAppStoreAPIDeveloperOtherApps(json *gjson.Result, iosId string) []*App {
+	list := json.Get("results")
+	if !list.IsArray() {
+		return nil
+	}
+
+	apps := make([]*App, 0)
+
+	list.ForEach(func(_, value gjson.Result) bool {
+		id := value.Get("trackId").String()
+		if value.Get("wrapperType").String() != "software" || id == iosId {
+			return true
+		}
+
+		apps = append(apps, &App{
+			ID:       id,
+			Name:     value.Get("trackName").String(),
+			Category: value.Get("primaryGenreName").String(),
+			Icon:     value.Get("artworkUrl100").String(),
+		})
+		return true
+	})
+
+	return apps
+}
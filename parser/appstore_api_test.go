@@ -0,0 +1,138 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-18 15:20:09
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-18 15:20:09
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseIOSDataFromAPI(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if req.URL.Host == "apps.apple.com" {
+			return 200, `<section class="section"><h2 class="section__headline">信息</h2>
+				<ul class="inline-list--app-extensions"><li class="inline-list__item"><a href="https://example.com/privacy">隐私政策</a></li></ul>
+			</section>`
+		}
+
+		// 开发者的全部应用
+		if req.URL.Query().Get("entity") == "software" {
+			return 200, `{"resultCount": 3, "results": [
+				{"wrapperType": "artist", "artistId": 1},
+				{"wrapperType": "software", "trackId": 123, "trackName": "抖音"},
+				{"wrapperType": "software", "trackId": 456, "trackName": "抖音极速版", "primaryGenreName": "娱乐", "artworkUrl100": "https://example.com/icon.png"}
+			]}`
+		}
+
+		return 200, `{"resultCount": 1, "results": [{
+			"trackName": "抖音 - 记录美好生活", "bundleId": "com.ss.iphone.ugc.Aweme", "artistId": 1,
+			"sellerName": "Beijing Co., Ltd.", "primaryGenreName": "娱乐", "averageUserRating": 4.8,
+			"userRatingCount": 12000, "fileSizeBytes": "268750029", "version": "28.5.0",
+			"currentVersionReleaseDate": "2023-07-03T07:00:00Z", "languageCodesISO2A": ["ZH", "EN"]
+		}]}`
+	})})
+
+	iosData, err := c.ParseIOSData(context.Background(), "123", &IOSOptions{Source: IOSSourceAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if iosData.IOSName != "抖音" || iosData.IOSBundleID != "com.ss.iphone.ugc.Aweme" {
+		t.Error("名称或 bundle id 取错了")
+	}
+
+	if iosData.IOSRateValue != 4.8 || iosData.IOSRateCountValue != 12000 || iosData.IOSPackageBytes != 268750029 {
+		t.Error("数字取错了")
+	}
+
+	if iosData.IOSLastUpdateTime.IsZero() {
+		t.Error("更新时间取错了")
+	}
+
+	if iosData.IOSLanguage != "ZH, EN" {
+		t.Error("language 取错了")
+	}
+
+	if iosData.IOSPrivacyPolicyUrl != "https://example.com/privacy" {
+		t.Error("隐私政策没有从页面取到")
+	}
+
+	if len(iosData.IOSOtherApps) != 1 || iosData.IOSOtherApps[0].ID != "456" {
+		t.Error("其他应用取错了")
+	}
+}
+
+func TestParseIOSDataFromAPINotFound(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if strings.HasPrefix(req.URL.Host, "itunes") {
+			return 200, `{"resultCount": 0, "results": []}`
+		}
+		return 200, ""
+	})})
+
+	_, err := c.ParseIOSData(context.Background(), "123", &IOSOptions{Source: IOSSourceAPI})
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("应该返回 ErrNotFound")
+	}
+}
+
+func TestParseIOSDataFromAPIWithoutPage(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if req.URL.Host == "apps.apple.com" {
+			return 403, ""
+		}
+
+		return 200, `{"resultCount": 1, "results": [{
+			"trackName": "抖音 - 记录美好生活", "bundleId": "com.ss.iphone.ugc.Aweme", "artistId": 1,
+			"minimumOsVersion": "11.0", "supportedDevices": ["iPhone12-iPhone12"], "screenshotUrls": ["https://example.com/1.png"]
+		}]}`
+	})})
+
+	iosData, err := c.ParseIOSData(context.Background(), "123", &IOSOptions{Source: IOSSourceAPI})
+	if err != nil {
+		t.Fatal("页面失败时应该返回接口数据", err)
+	}
+
+	if iosData.IOSName != "抖音" || iosData.IOSBundleID != "com.ss.iphone.ugc.Aweme" {
+		t.Error("名称或 bundle id 取错了")
+	}
+
+	if iosData.IOSPrivacyPolicyUrl != "" || len(iosData.IOSPrivacyLabels) != 0 {
+		t.Error("页面上的字段应该为空")
+	}
+
+	if len(iosData.IOSCompatibility) == 0 || len(iosData.IOSMedia.Screenshots) != 1 {
+		t.Error("应该用接口的兼容性与截图")
+	}
+}
+
+func TestParseIOSDataFromAPIWithoutArtist(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if req.URL.Host == "apps.apple.com" {
+			return 200, ""
+		}
+
+		if req.URL.Query().Get("entity") != "" {
+			t.Error("没有 artistId 时不应该查询开发者的应用", req.URL)
+		}
+
+		return 200, `{"resultCount": 1, "results": [{"trackName": "抖音"}]}`
+	})})
+
+	iosData, err := c.ParseIOSData(context.Background(), "123", &IOSOptions{Source: IOSSourceAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(iosData.IOSOtherApps) != 0 {
+		t.Error("其他应用应该为空")
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-17 10:25:13
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-17 10:25:13
+ * @Description:
+ */
+package parser
+
+import (
+	"net/url"
+	"strings"
+)
+
+// app store 默认的区域
+const defaultAppStoreCountry = "cn"
+
+// ios 数据的来源
+type IOSSource string
+
+const (
+	IOSSourceHTML IOSSource = "html" // 抓取详情页面，默认
+	IOSSourceAPI  IOSSource = "api"  // 使用 itunes lookup 接口，接口没有的字段（内购、隐私政策）再抓取详情页面
+)
+
+// app store 的区域、语言与数据来源
+type IOSOptions struct {
+	Country  string    // 区域，例：cn、us、jp、hk、tw，默认 cn
+	Language string    // 语言，例：zh-cn、en-us、ja-jp、zh-hk、zh-tw，为空时使用区域的默认语言
+	Source   IOSSource // 数据来源，默认 IOSSourceHTML
+	Platform string    // 平台，例：iphone、ipad、mac、appletv，默认 iphone，指定 mac 时按 mac app store 搜索并打开 mac 版页面
+
+	ScreenshotWidth int // 截图宽度，从页面提供的尺寸中取不小于该宽度的最小尺寸，默认取最大的
+}
+
+// 详情页面上各块内容的标题，不同语言的页面文案不一样
+type appStoreLocale struct {
+	Information         []string // 信息
+	Ratings             []string // 评分及评论
+	Seller              []string // 供应商
+	Size                []string // 大小
+	Category            []string // 类别
+	Languages           []string // 语言
+	RatingCount         []string // 评价数后面的文案，例：1.2万个评分
+	Version             []string // 版本号前面的文案，例：版本 28.5.0
+	Compatibility       []string // 兼容性
+	Privacy             []string // App 隐私
+	PrivacyTrack        []string // 用于追踪您的数据
+	PrivacyLinked       []string // 与您关联的数据
+	PrivacyNotLinked    []string // 未与您关联的数据
+	PrivacyNotCollected []string // 未收集数据
+}
+
+// 各区域的默认语言
+var appStoreCountryLanguages = map[string]string{
+	"cn": "zh-cn",
+	"us": "en-us",
+	"jp": "ja-jp",
+	"hk": "zh-hk",
+	"tw": "zh-tw",
+}
+
+// 各语言的标题文案
+var appStoreLocales = map[string]*appStoreLocale{
+	"zh-cn": {
+		Information:         []string{"信息"},
+		Ratings:             []string{"评分及评论"},
+		Seller:              []string{"供应商"},
+		Size:                []string{"大小"},
+		Category:            []string{"类别", "类別"},
+		Languages:           []string{"语言"},
+		RatingCount:         []string{"个评分"},
+		Version:             []string{"版本"},
+		Compatibility:       []string{"兼容性"},
+		Privacy:             []string{"App 隐私"},
+		PrivacyTrack:        []string{"用于追踪您的数据"},
+		PrivacyLinked:       []string{"与您关联的数据"},
+		PrivacyNotLinked:    []string{"未与您关联的数据"},
+		PrivacyNotCollected: []string{"未收集数据"},
+	},
+	"en-us": {
+		Information:         []string{"Information"},
+		Ratings:             []string{"Ratings and Reviews", "Ratings & Reviews"},
+		Seller:              []string{"Seller", "Provider"},
+		Size:                []string{"Size"},
+		Category:            []string{"Category"},
+		Languages:           []string{"Languages", "Language"},
+		RatingCount:         []string{"Ratings", "Rating"},
+		Version:             []string{"Version"},
+		Compatibility:       []string{"Compatibility"},
+		Privacy:             []string{"App Privacy"},
+		PrivacyTrack:        []string{"Data Used to Track You"},
+		PrivacyLinked:       []string{"Data Linked to You"},
+		PrivacyNotLinked:    []string{"Data Not Linked to You"},
+		PrivacyNotCollected: []string{"Data Not Collected"},
+	},
+	"ja-jp": {
+		Information:         []string{"情報"},
+		Ratings:             []string{"評価とレビュー"},
+		Seller:              []string{"販売元", "提供元"},
+		Size:                []string{"サイズ"},
+		Category:            []string{"カテゴリ"},
+		Languages:           []string{"言語"},
+		RatingCount:         []string{"件の評価"},
+		Version:             []string{"バージョン"},
+		Compatibility:       []string{"互換性"},
+		Privacy:             []string{"Appのプライバシー"},
+		PrivacyTrack:        []string{"ユーザのトラッキングに使用されるデータ"},
+		PrivacyLinked:       []string{"ユーザに関連付けられたデータ"},
+		PrivacyNotLinked:    []string{"ユーザに関連付けられないデータ"},
+		PrivacyNotCollected: []string{"データの収集なし"},
+	},
+	"zh-hk": {
+		Information:         []string{"資料"},
+		Ratings:             []string{"評分及評論"},
+		Seller:              []string{"供應商"},
+		Size:                []string{"大小"},
+		Category:            []string{"類別"},
+		Languages:           []string{"語言"},
+		RatingCount:         []string{"個評分"},
+		Version:             []string{"版本"},
+		Compatibility:       []string{"相容性"},
+		Privacy:             []string{"App 私隱"},
+		PrivacyTrack:        []string{"用於追蹤你的資料"},
+		PrivacyLinked:       []string{"與你連結的資料"},
+		PrivacyNotLinked:    []string{"不會與你連結的資料"},
+		PrivacyNotCollected: []string{"不收集資料"},
+	},
+	"zh-tw": {
+		Information:         []string{"資訊"},
+		Ratings:             []string{"評分與評論"},
+		Seller:              []string{"供應商"},
+		Size:                []string{"大小"},
+		Category:            []string{"類別"},
+		Languages:           []string{"語言"},
+		RatingCount:         []string{"則評分"},
+		Version:             []string{"版本"},
+		Compatibility:       []string{"相容性"},
+		Privacy:             []string{"App 隱私權"},
+		PrivacyTrack:        []string{"用來追蹤你的資料"},
+		PrivacyLinked:       []string{"與你連結的資料"},
+		PrivacyNotLinked:    []string{"未與你連結的資料"},
+		PrivacyNotCollected: []string{"未收集資料"},
+	},
+}
+
+// 区域，默认 cn
+func (o *IOSOptions) country() string {
+	if o == nil || o.Country == "" {
+		return defaultAppStoreCountry
+	}
+	return strings.ToLower(o.Country)
+}
+
+// 数据来源，默认抓取详情页面
+func (o *IOSOptions) source() IOSSource {
+	if o == nil || o.Source == "" {
+		return IOSSourceHTML
+	}
+	return o.Source
+}
+
+// 平台，默认 iphone
+func (o *IOSOptions) platform() string {
+	if o == nil || o.Platform == "" {
+		return IOSPlatformIPhone
+	}
+	return strings.ToLower(o.Platform)
+}
+
+// 搜索接口的 entity，mac 与 ipad 应用在单独的分类下
+func (o *IOSOptions) searchEntity() string {
+	switch o.platform() {
+	case IOSPlatformMac:
+		return "macSoftware"
+	case IOSPlatformIPad:
+		return "iPadSoftware"
+	}
+	return "software"
+}
+
+// 截图宽度，0 为取最大的
+func (o *IOSOptions) screenshotWidth() int {
+	if o == nil || o.ScreenshotWidth < 0 {
+		return 0
+	}
+	return o.ScreenshotWidth
+}
+
+// 语言，为空时使用区域的默认语言
+func (o *IOSOptions) language() string {
+	if o != nil && o.Language != "" {
+		return strings.ToLower(o.Language)
+	}
+	return appStoreCountryLanguages[o.country()]
+}
+
+// 页面标题文案，不支持的语言使用英文
+func (o *IOSOptions) locale() *appStoreLocale {
+	if l, ok := appStoreLocales[o.language()]; ok {
+		return l
+	}
+	return appStoreLocales["en-us"]
+}
+
+// 详情页面地址，query 为额外的参数
+func (o *IOSOptions) appURL(id string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	// 指定了语言时才带上 l 参数，否则使用区域的默认语言
+	if o != nil && o.Language != "" {
+		query.Set("l", o.language())
+	}
+
+	// 多平台的应用默认展示 iphone 版页面，其他平台需要带上 platform 参数
+	if o.platform() != IOSPlatformIPhone {
+		query.Set("platform", o.platform())
+	}
+
+	u := "https://apps.apple.com/" + o.country() + "/app/id" + id
+	if len(query) > 0 {
+		return u + "?" + query.Encode()
+	}
+
+	return u + "/"
+}
+
+// 取第一个可用的配置
+func firstIOSOptions(opts []*IOSOptions) *IOSOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return nil
+}
+
+// text 以 prefixes 中任意一个开头时，返回去掉前缀的内容
+func trimAnyPrefix(text string, prefixes []string) (string, bool) {
+	for _, p := range prefixes {
+		if strings.HasPrefix(text, p) {
+			return strings.TrimPrefix(text, p), true
+		}
+	}
+	return text, false
+}
@@ -0,0 +1,78 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-17 11:12:40
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-17 11:12:40
+ * @Description:
+ */
+package parser
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestIOSOptionsAppURL(t *testing.T) {
+	var opts *IOSOptions
+	if u := opts.appURL("123", nil); u != "https://apps.apple.com/cn/app/id123/" {
+		t.Error("默认地址错了", u)
+	}
+
+	opts = &IOSOptions{Country: "US"}
+	if u := opts.appURL("123", nil); u != "https://apps.apple.com/us/app/id123/" {
+		t.Error("us 地址错了", u)
+	}
+
+	opts = &IOSOptions{Country: "hk", Language: "en-us"}
+	u := opts.appURL("123", url.Values{"see-all": {"developer-other-apps"}})
+	if u != "https://apps.apple.com/hk/app/id123?l=en-us&see-all=developer-other-apps" {
+		t.Error("hk 英文地址错了", u)
+	}
+
+	if opts.locale() != appStoreLocales["en-us"] {
+		t.Error("指定语言后没有使用对应的文案")
+	}
+
+	if (&IOSOptions{Country: "jp"}).locale() != appStoreLocales["ja-jp"] {
+		t.Error("jp 没有使用日文文案")
+	}
+}
+
+func TestGetAppStoreInfoItemLocale(t *testing.T) {
+	html := `
+	<section class="section">
+		<h2 class="section__headline">Information</h2>
+		<dl class="information-list">
+			<div class="information-list__item"><dt>Seller</dt><dd> Beijing Co., Ltd. </dd></div>
+			<div class="information-list__item"><dt>Size</dt><dd>256.3 MB</dd></div>
+			<div class="information-list__item"><dt>Category</dt><dd>Entertainment</dd></div>
+		</dl>
+	</section>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &IOSOptions{Country: "us"}
+
+	if v := getAppStoreSupplier(doc, opts); v != "Beijing Co., Ltd." {
+		t.Error("supplier 取错了", v)
+	}
+
+	if v := getAppStorePackageSize(doc, opts); v != "256.3 MB" {
+		t.Error("size 取错了", v)
+	}
+
+	if v := getAppStoreCategory(doc, opts); v != "Entertainment" {
+		t.Error("category 取错了", v)
+	}
+
+	// 中文文案匹配不到英文页面
+	if v := getAppStoreCategory(doc, nil); v != "" {
+		t.Error("category 不应该取到", v)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-02 11:20:45
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-02 11:20:45
+ * @Description:
+ */
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// 截图与视频所属的平台，例：we-artwork--screenshot-platform-iphone
+var appStoreMediaPlatformReg = regexp.MustCompile(`platform-([a-z]+)`)
+
+// 截图的格式，按顺序优先
+var appStoreScreenshotTypes = []string{"image/png", "image/jpeg", "image/webp"}
+
+// lookup 接口中各设备的截图字段
+var appStoreAPIScreenshotKeys = []struct {
+	key    string
+	device string
+}{
+	{"screenshotUrls", IOSPlatformIPhone},
+	{"ipadScreenshotUrls", IOSPlatformIPad},
+	{"appletvScreenshotUrls", IOSPlatformAppleTV},
+}
+
+// 获取截图与预览视频，截图的尺寸由 opts.ScreenshotWidth 决定
+func getAppStoreMedia(doc *goquery.Document, opts *IOSOptions) Media {
+	media := Media{
+		Screenshots: make([]*Screenshot, 0),
+		Videos:      make([]*Video, 0),
+	}
+
+	doc.Find(".we-screenshot-viewer__screenshots picture").Each(func(i int, s *goquery.Selection) {
+		srcset := ""
+		for _, t := range appStoreScreenshotTypes {
+			srcset = s.Find("source[type='"+t+"']").AttrOr("srcset", "")
+			if srcset != "" {
+				break
+			}
+		}
+
+		if srcset == "" {
+			return
+		}
+
+		u, w := pickSrcset(srcset, opts.screenshotWidth())
+		media.Screenshots = append(media.Screenshots, &Screenshot{
+			Device: getAppStoreMediaDevice(s),
+			URL:    u,
+			Width:  w,
+		})
+	})
+
+	// 只取截图区域中的预览视频，页面上其他位置的视频不算
+	doc.Find(".we-screenshot-viewer__screenshots video").Each(func(i int, s *goquery.Selection) {
+		u := s.AttrOr("src", "")
+		if u == "" {
+			u = s.Find("source[src]").AttrOr("src", "")
+		}
+
+		if u == "" {
+			return
+		}
+
+		media.Videos = append(media.Videos, &Video{
+			Device: getAppStoreMediaDevice(s),
+			URL:    strings.TrimSpace(u),
+			Poster: strings.TrimSpace(s.AttrOr("poster", "")),
+		})
+	})
+
+	return media
+}
+
+// 截图或视频所属的平台，样式名在元素自身或外层上，取不到时为空
+func getAppStoreMediaDevice(s *goquery.Selection) string {
+	for node := s; node.Length() > 0; node = node.Parent() {
+		if m := appStoreMediaPlatformReg.FindStringSubmatch(node.AttrOr("class", "")); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
+
+// 获取 lookup 接口中的截图，接口只有固定尺寸，没有预览视频
+func getAppStoreAPIMedia(json *gjson.Result) Media {
+	media := Media{
+		Screenshots: make([]*Screenshot, 0),
+		Videos:      make([]*Video, 0),
+	}
+
+	for _, v := range appStoreAPIScreenshotKeys {
+		json.Get(v.key).ForEach(func(_, value gjson.Result) bool {
+			media.Screenshots = append(media.Screenshots, &Screenshot{
+				Device: v.device,
+				URL:    value.String(),
+			})
+			return true
+		})
+	}
+
+	return media
+}
@@ -0,0 +1,67 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-02 15:06:48
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-02 15:06:48
+ * @Description:
+ */
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+func TestGetAppStoreMedia(t *testing.T) {
+	html := `
+	<div class="we-screenshot-viewer__screenshots">
+		<ul>
+			<li><div class="we-video-wrapper we-video--platform-iphone">
+				<video src="https://a/preview.m3u8" poster="https://a/poster.jpg"></video>
+			</div></li>
+			<li><picture class="we-artwork we-artwork--screenshot-platform-iphone">
+				<source srcset="https://a/1.webp 300w, https://a/1x.webp 600w" type="image/webp">
+				<source srcset="https://a/1.jpg 300w, https://a/1x.jpg 600w" type="image/jpeg">
+			</picture></li>
+		</ul>
+	</div>
+	<div class="we-screenshot-viewer__screenshots">
+		<picture class="we-artwork we-artwork--screenshot-platform-ipad">
+			<source srcset="https://a/2.png 576w, https://a/2x.png 1152w" type="image/png">
+		</picture>
+	</div>
+	<section class="section--editorial"><video src="https://a/editorial.m3u8"></video></section>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	media := getAppStoreMedia(doc, &IOSOptions{ScreenshotWidth: 300})
+
+	iphone := media.ScreenshotsFor(IOSPlatformIPhone)
+	if len(iphone) != 1 || iphone[0].URL != "https://a/1.jpg" || iphone[0].Width != 300 {
+		t.Error("iphone 截图取错了", iphone)
+	}
+
+	ipad := media.ScreenshotsFor(IOSPlatformIPad)
+	if len(ipad) != 1 || ipad[0].URL != "https://a/2.png" {
+		t.Error("ipad 截图取错了", ipad)
+	}
+
+	if len(media.Videos) != 1 || media.Videos[0].Device != IOSPlatformIPhone || media.Videos[0].Poster != "https://a/poster.jpg" {
+		t.Error("视频取错了", media.Videos)
+	}
+}
+
+func TestGetAppStoreAPIMedia(t *testing.T) {
+	json := gjson.Parse(`{"screenshotUrls": ["https://a/1.jpg", "https://a/2.jpg"], "ipadScreenshotUrls": ["https://a/3.jpg"]}`)
+
+	media := getAppStoreAPIMedia(&json)
+	if len(media.ScreenshotsFor(IOSPlatformIPhone)) != 2 || len(media.ScreenshotsFor(IOSPlatformIPad)) != 1 {
+		t.Error("接口截图取错了")
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-28 10:36:14
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-28 10:36:14
+ * @Description:
+ */
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// apple 的平台
+const (
+	IOSPlatformIPhone  = "iphone"
+	IOSPlatformIPad    = "ipad"
+	IOSPlatformIPod    = "ipod"
+	IOSPlatformMac     = "mac"
+	IOSPlatformAppleTV = "appletv"
+	IOSPlatformWatch   = "watch"
+	IOSPlatformVision  = "vision"
+)
+
+// lookup 接口中 mac 应用的 kind
+const iosKindMac = "mac-software"
+
+// 平台的排列顺序
+var iosPlatformOrder = []string{
+	IOSPlatformIPhone,
+	IOSPlatformIPad,
+	IOSPlatformIPod,
+	IOSPlatformMac,
+	IOSPlatformAppleTV,
+	IOSPlatformWatch,
+	IOSPlatformVision,
+}
+
+// 设备名称与平台的对应关系，按前缀匹配，页面上是 Apple TV，接口中是 AppleTV4-AppleTV4
+var iosDevicePrefixes = []struct {
+	prefix   string
+	platform string
+}{
+	{"iphone", IOSPlatformIPhone},
+	{"ipad", IOSPlatformIPad},
+	{"ipod", IOSPlatformIPod},
+	{"appletv", IOSPlatformAppleTV},
+	{"applewatch", IOSPlatformWatch},
+	{"watch", IOSPlatformWatch},
+	{"applevision", IOSPlatformVision},
+	{"reality", IOSPlatformVision},
+	{"mac", IOSPlatformMac},
+}
+
+// 一个平台的兼容性
+type IOSCompatibility struct {
+	Platform         string `bson:"platform"`           // 平台，例：iphone、mac
+	Device           string `bson:"device"`             // 页面上展示的设备名称，例：iPhone、Apple TV
+	MinimumOSVersion string `bson:"minimum_os_version"` // 最低系统版本，例：12.0
+	Requirement      string `bson:"requirement"`        // 页面上的兼容性要求，例：需要 iOS 12.0 或更高版本。
+}
+
+// 是否支持某个平台，p 为 IOSPlatformIPhone 等
+func (d *IOSData) SupportsPlatform(p string) bool {
+	for _, v := range d.IOSPlatforms {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+// 是否为 mac app store 的应用
+func (d *IOSData) IsMacApp() bool {
+	if d.IOSKind != "" {
+		return d.IOSKind == iosKindMac
+	}
+	return len(d.IOSPlatforms) == 1 && d.IOSPlatforms[0] == IOSPlatformMac
+}
+
+// 设备名称对应的平台，不认识的设备返回空
+func iosDevicePlatform(device string) string {
+	name := strings.ToLower(strings.ReplaceAll(device, " ", ""))
+
+	for _, v := range iosDevicePrefixes {
+		if strings.HasPrefix(name, v.prefix) {
+			return v.platform
+		}
+	}
+
+	return ""
+}
+
+// 兼容性要求中的系统版本，例：需要 iOS 12.0 或更高版本。
+var iosMinimumOSReg = regexp.MustCompile(`(?:iOS|iPadOS|macOS|OS X|tvOS|watchOS|visionOS)\s*([0-9]+(?:\.[0-9]+)*)`)
+
+// 获取兼容性，详情页面信息块中的兼容性一项，每个设备一行
+func getAppStoreCompatibility(doc *goquery.Document, opts *IOSOptions) []*IOSCompatibility {
+	sel := getAppStoreInfoItemSelection(doc, opts, opts.locale().Compatibility)
+	items := sel.Find("dd dl")
+	if items.Length() == 0 {
+		return nil
+	}
+
+	list := make([]*IOSCompatibility, 0)
+
+	items.Each(func(i int, s *goquery.Selection) {
+		device := strings.TrimSpace(s.Find("dt").Text())
+		platform := iosDevicePlatform(device)
+		if platform == "" {
+			return
+		}
+
+		item := &IOSCompatibility{
+			Platform:    platform,
+			Device:      device,
+			Requirement: strings.TrimSpace(s.Find("dd").Text()),
+		}
+
+		if m := iosMinimumOSReg.FindStringSubmatch(item.Requirement); m != nil {
+			item.MinimumOSVersion = m[1]
+		}
+
+		list = append(list, item)
+	})
+
+	return list
+}
+
+// 获取 lookup 接口中支持的设备型号
+func getAppStoreLookupDevices(json *gjson.Result) []string {
+	if json == nil {
+		return nil
+	}
+
+	devices := json.Get("results.0.supportedDevices")
+	if !devices.IsArray() {
+		return nil
+	}
+
+	list := make([]string, 0)
+
+	devices.ForEach(func(_, value gjson.Result) bool {
+		list = append(list, value.String())
+		return true
+	})
+
+	return list
+}
+
+// 获取兼容性，接口只有一个最低系统版本，iphone 与 ipad 共用，其他平台取不到
+func getAppStoreAPICompatibility(json *gjson.Result) []*IOSCompatibility {
+	minimum := getAppStoreAPIString(json, "minimumOsVersion")
+
+	if getAppStoreAPIString(json, "kind") == iosKindMac {
+		return []*IOSCompatibility{{Platform: IOSPlatformMac, Device: "Mac", MinimumOSVersion: minimum}}
+	}
+
+	devices := make([]string, 0)
+	json.Get("supportedDevices").ForEach(func(_, value gjson.Result) bool {
+		devices = append(devices, value.String())
+		return true
+	})
+
+	list := make([]*IOSCompatibility, 0)
+
+	for _, platform := range getIOSPlatforms("", nil, devices) {
+		item := &IOSCompatibility{Platform: platform}
+		if platform == IOSPlatformIPhone || platform == IOSPlatformIPad || platform == IOSPlatformIPod {
+			item.MinimumOSVersion = minimum
+		}
+		list = append(list, item)
+	}
+
+	return list
+}
+
+// 汇总支持的平台，按 iosPlatformOrder 排序，mac 应用的接口数据中没有设备型号
+func getIOSPlatforms(kind string, compatibility []*IOSCompatibility, devices []string) []string {
+	found := make(map[string]bool)
+
+	if kind == iosKindMac {
+		found[IOSPlatformMac] = true
+	}
+
+	for _, v := range compatibility {
+		found[v.Platform] = true
+	}
+
+	for _, v := range devices {
+		if p := iosDevicePlatform(v); p != "" {
+			found[p] = true
+		}
+	}
+
+	list := make([]string, 0)
+	for _, p := range iosPlatformOrder {
+		if found[p] {
+			list = append(list, p)
+		}
+	}
+
+	return list
+}
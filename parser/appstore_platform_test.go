@@ -0,0 +1,114 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-28 11:45:02
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-28 11:45:02
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestGetAppStoreCompatibility(t *testing.T) {
+	html := `
+	<section class="section">
+		<h2 class="section__headline">信息</h2>
+		<dl class="information-list">
+			<div class="information-list__item"><dt>大小</dt><dd>256.3 MB</dd></div>
+			<div class="information-list__item">
+				<dt>兼容性</dt>
+				<dd>
+					<dl><dt>iPhone</dt><dd>需要 iOS 12.0 或更高版本。</dd></dl>
+					<dl><dt>iPad</dt><dd>需要 iPadOS 12.0 或更高版本。</dd></dl>
+					<dl><dt>Mac</dt><dd>需要 macOS 11.0 或更高版本以及装配 Apple M1 芯片或更高版本的 Mac。</dd></dl>
+					<dl><dt>Apple TV</dt><dd>需要 tvOS 14.0 或更高版本。</dd></dl>
+				</dd>
+			</div>
+		</dl>
+	</section>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := getAppStoreCompatibility(doc, nil)
+	if len(list) != 4 {
+		t.Fatal("compatibility 数量错了", len(list))
+	}
+
+	if list[2].Platform != IOSPlatformMac || list[2].MinimumOSVersion != "11.0" {
+		t.Error("mac 取错了", list[2])
+	}
+
+	if list[3].Platform != IOSPlatformAppleTV || list[3].MinimumOSVersion != "14.0" {
+		t.Error("apple tv 取错了", list[3])
+	}
+
+	// 兼容性的内容不影响其他项
+	if v := getAppStorePackageSize(doc, nil); v != "256.3 MB" {
+		t.Error("size 取错了", v)
+	}
+}
+
+func TestGetIOSPlatforms(t *testing.T) {
+	devices := []string{"iPhone5s-iPhone5s", "iPadAir2-iPadAir2", "AppleTV4-AppleTV4", "Watch4-Watch4", "Unknown"}
+
+	want := []string{IOSPlatformIPhone, IOSPlatformIPad, IOSPlatformAppleTV, IOSPlatformWatch}
+	if got := getIOSPlatforms("software", nil, devices); !reflect.DeepEqual(got, want) {
+		t.Error("platforms 取错了", got)
+	}
+
+	if got := getIOSPlatforms(iosKindMac, nil, nil); !reflect.DeepEqual(got, []string{IOSPlatformMac}) {
+		t.Error("mac 应用的 platforms 取错了", got)
+	}
+}
+
+func TestIOSOptionsPlatform(t *testing.T) {
+	opts := &IOSOptions{Platform: "Mac"}
+	if u := opts.appURL("123", nil); u != "https://apps.apple.com/cn/app/id123?platform=mac" {
+		t.Error("mac 地址错了", u)
+	}
+
+	if opts.searchEntity() != "macSoftware" {
+		t.Error("mac 的 entity 错了")
+	}
+
+	if (*IOSOptions)(nil).searchEntity() != "software" {
+		t.Error("默认的 entity 错了")
+	}
+}
+
+func TestParseIOSDataMacFromAPI(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if req.URL.Host == "apps.apple.com" {
+			return 200, `<html></html>`
+		}
+
+		return 200, `{"resultCount": 1, "results": [{
+			"kind": "mac-software", "trackName": "Xcode", "bundleId": "com.apple.dt.Xcode",
+			"minimumOsVersion": "13.0", "version": "14.3.1"
+		}]}`
+	})})
+
+	iosData, err := c.ParseIOSData(context.Background(), "497799835", &IOSOptions{Source: IOSSourceAPI, Platform: IOSPlatformMac})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !iosData.IsMacApp() || !iosData.SupportsPlatform(IOSPlatformMac) || iosData.SupportsPlatform(IOSPlatformIPhone) {
+		t.Error("mac 应用判断错了", iosData.IOSPlatforms)
+	}
+
+	if len(iosData.IOSCompatibility) != 1 || iosData.IOSCompatibility[0].MinimumOSVersion != "13.0" {
+		t.Error("最低系统版本取错了")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-07 10:31:58
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-07 10:31:58
+ * @Description:
+ */
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 隐私标签的类型
+const (
+	IOSPrivacyTrack        = "track"         // 用于追踪您的数据
+	IOSPrivacyLinked       = "linked"        // 与您关联的数据
+	IOSPrivacyNotLinked    = "not-linked"    // 未与您关联的数据
+	IOSPrivacyNotCollected = "not-collected" // 未收集数据
+)
+
+// app store 的隐私标签，每种类型一个
+type IOSPrivacyLabel struct {
+	Type       string                `bson:"type"`       // 类型，例：IOSPrivacyLinked
+	Title      string                `bson:"title"`      // 页面上的标题，例：与您关联的数据
+	Categories []*IOSPrivacyCategory `bson:"categories"` // 数据类别
+}
+
+// 隐私标签中的一个数据类别
+type IOSPrivacyCategory struct {
+	Name      string   `bson:"name"`       // 类别，例：联系信息
+	DataTypes []string `bson:"data_types"` // 数据类型，例：电子邮件地址，页面上没有展开时为空
+}
+
+// 隐私标签中的一项，用于比较
+type IOSPrivacyItem struct {
+	Type     string `bson:"type"`      // 标签类型
+	Category string `bson:"category"`  // 数据类别
+	DataType string `bson:"data_type"` // 数据类型，没有时为空
+}
+
+// 两个版本隐私标签的差异
+type IOSPrivacyDiff struct {
+	Added   []*IOSPrivacyItem `bson:"added"`   // 新增的项
+	Removed []*IOSPrivacyItem `bson:"removed"` // 去掉的项
+}
+
+// 是否没有差异
+func (d *IOSPrivacyDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// 获取隐私标签，标签的类型根据卡片标题判断
+func getAppStorePrivacyLabels(doc *goquery.Document, opts *IOSOptions) []*IOSPrivacyLabel {
+	sel := getAppStoreSection(doc, opts.locale().Privacy)
+	cards := sel.Find(".app-privacy__card")
+	if cards.Length() == 0 {
+		return nil
+	}
+
+	labels := make([]*IOSPrivacyLabel, 0)
+
+	cards.Each(func(i int, s *goquery.Selection) {
+		title := strings.TrimSpace(s.Find(".privacy-type__heading").First().Text())
+
+		label := &IOSPrivacyLabel{
+			Type:       getAppStorePrivacyType(title, opts),
+			Title:      title,
+			Categories: make([]*IOSPrivacyCategory, 0),
+		}
+
+		if label.Type == "" {
+			return
+		}
+
+		s.Find(".privacy-type__item").Each(func(j int, item *goquery.Selection) {
+			category := &IOSPrivacyCategory{
+				Name:      strings.TrimSpace(item.Find(".privacy-type__data-category-heading").Text()),
+				DataTypes: make([]string, 0),
+			}
+
+			item.Find(".privacy-type__data-types li").Each(func(k int, v *goquery.Selection) {
+				if t := strings.TrimSpace(v.Text()); t != "" {
+					category.DataTypes = append(category.DataTypes, t)
+				}
+			})
+
+			if category.Name != "" {
+				label.Categories = append(label.Categories, category)
+			}
+		})
+
+		labels = append(labels, label)
+	})
+
+	return labels
+}
+
+// 根据卡片标题判断隐私标签的类型，不认识的标题返回空
+func getAppStorePrivacyType(title string, opts *IOSOptions) string {
+	l := opts.locale()
+
+	types := []struct {
+		titles []string
+		t      string
+	}{
+		{l.PrivacyTrack, IOSPrivacyTrack},
+		{l.PrivacyNotLinked, IOSPrivacyNotLinked},
+		{l.PrivacyLinked, IOSPrivacyLinked},
+		{l.PrivacyNotCollected, IOSPrivacyNotCollected},
+	}
+
+	for _, v := range types {
+		if _, ok := trimAnyPrefix(title, v.titles); ok {
+			return v.t
+		}
+	}
+
+	return ""
+}
+
+// 把隐私标签展开成一项一项，没有数据类型的类别作为一项
+func flattenPrivacyLabels(labels []*IOSPrivacyLabel) map[IOSPrivacyItem]bool {
+	items := make(map[IOSPrivacyItem]bool)
+
+	for _, label := range labels {
+		if len(label.Categories) == 0 {
+			items[IOSPrivacyItem{Type: label.Type}] = true
+		}
+
+		for _, category := range label.Categories {
+			if len(category.DataTypes) == 0 {
+				items[IOSPrivacyItem{Type: label.Type, Category: category.Name}] = true
+			}
+
+			for _, t := range category.DataTypes {
+				items[IOSPrivacyItem{Type: label.Type, Category: category.Name, DataType: t}] = true
+			}
+		}
+	}
+
+	return items
+}
+
+// 比较两个版本的隐私标签，before 为旧的，after 为新的，例如上一次抓取的结果与这一次的，或者自己的声明与线上的
+func DiffPrivacyLabels(before, after []*IOSPrivacyLabel) *IOSPrivacyDiff {
+	oldItems := flattenPrivacyLabels(before)
+	newItems := flattenPrivacyLabels(after)
+
+	diff := &IOSPrivacyDiff{
+		Added:   make([]*IOSPrivacyItem, 0),
+		Removed: make([]*IOSPrivacyItem, 0),
+	}
+
+	for item := range newItems {
+		if !oldItems[item] {
+			item := item
+			diff.Added = append(diff.Added, &item)
+		}
+	}
+
+	for item := range oldItems {
+		if !newItems[item] {
+			item := item
+			diff.Removed = append(diff.Removed, &item)
+		}
+	}
+
+	sortPrivacyItems(diff.Added)
+	sortPrivacyItems(diff.Removed)
+
+	return diff
+}
+
+// 按类型、类别、数据类型排序，map 的遍历顺序不固定
+func sortPrivacyItems(list []*IOSPrivacyItem) {
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.DataType < b.DataType
+	})
+}
@@ -0,0 +1,94 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-07 11:47:20
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-07 11:47:20
+ * @Description:
+ */
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestGetAppStorePrivacyLabels(t *testing.T) {
+	html := `
+	<section class="section app-privacy">
+		<h2 class="section__headline">App 隐私</h2>
+		<div class="app-privacy__cards">
+			<div class="app-privacy__card">
+				<h3 class="privacy-type__heading">用于追踪您的数据</h3>
+				<ul class="privacy-type__items">
+					<li class="privacy-type__item"><span class="privacy-type__data-category-heading">标识符</span></li>
+				</ul>
+			</div>
+			<div class="app-privacy__card">
+				<h3 class="privacy-type__heading">与您关联的数据</h3>
+				<ul class="privacy-type__items">
+					<li class="privacy-type__item">
+						<span class="privacy-type__data-category-heading">联系信息</span>
+						<ul class="privacy-type__data-types"><li>电子邮件地址</li><li>电话号码</li></ul>
+					</li>
+				</ul>
+			</div>
+			<div class="app-privacy__card">
+				<h3 class="privacy-type__heading">未与您关联的数据</h3>
+				<ul class="privacy-type__items">
+					<li class="privacy-type__item"><span class="privacy-type__data-category-heading">诊断</span></li>
+				</ul>
+			</div>
+		</div>
+	</section>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := getAppStorePrivacyLabels(doc, nil)
+	if len(labels) != 3 {
+		t.Fatal("标签数量错了", len(labels))
+	}
+
+	if labels[0].Type != IOSPrivacyTrack || labels[1].Type != IOSPrivacyLinked || labels[2].Type != IOSPrivacyNotLinked {
+		t.Error("标签类型错了")
+	}
+
+	linked := labels[1].Categories
+	if len(linked) != 1 || linked[0].Name != "联系信息" || len(linked[0].DataTypes) != 2 {
+		t.Error("数据类别取错了", linked)
+	}
+}
+
+func TestDiffPrivacyLabels(t *testing.T) {
+	before := []*IOSPrivacyLabel{
+		{Type: IOSPrivacyLinked, Categories: []*IOSPrivacyCategory{{Name: "联系信息", DataTypes: []string{"电子邮件地址"}}}},
+		{Type: IOSPrivacyNotLinked, Categories: []*IOSPrivacyCategory{{Name: "诊断"}}},
+	}
+
+	after := []*IOSPrivacyLabel{
+		{Type: IOSPrivacyTrack, Categories: []*IOSPrivacyCategory{{Name: "标识符"}}},
+		{Type: IOSPrivacyLinked, Categories: []*IOSPrivacyCategory{{Name: "联系信息", DataTypes: []string{"电子邮件地址", "电话号码"}}}},
+	}
+
+	diff := DiffPrivacyLabels(before, after)
+
+	if len(diff.Added) != 2 || len(diff.Removed) != 1 {
+		t.Fatal("差异数量错了", len(diff.Added), len(diff.Removed))
+	}
+
+	if *diff.Added[0] != (IOSPrivacyItem{Type: IOSPrivacyLinked, Category: "联系信息", DataType: "电话号码"}) {
+		t.Error("新增的项错了", diff.Added[0])
+	}
+
+	if *diff.Removed[0] != (IOSPrivacyItem{Type: IOSPrivacyNotLinked, Category: "诊断"}) {
+		t.Error("去掉的项错了", diff.Removed[0])
+	}
+
+	if !DiffPrivacyLabels(after, after).IsEmpty() {
+		t.Error("相同的标签不应该有差异")
+	}
+}
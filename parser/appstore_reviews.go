@@ -0,0 +1,187 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-31 10:12:27
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-31 10:12:27
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// 评论的排序
+type IOSReviewSort string
+
+const (
+	IOSReviewSortRecent  IOSReviewSort = "mostrecent"  // 最新，默认
+	IOSReviewSortHelpful IOSReviewSort = "mosthelpful" // 最有帮助
+)
+
+// 评论接口最多能翻的页数，每页 50 条
+const maxIOSReviewPages = 10
+
+// 获取评论的配置
+type IOSReviewOptions struct {
+	Sort     IOSReviewSort // 排序，默认 IOSReviewSortRecent
+	MaxPages int           // 最多翻几页，默认也是最大 10 页
+	Limit    int           // 最多取多少条，0 为不限制
+}
+
+// app store 的一条评论
+type IOSReview struct {
+	ID        string    `bson:"id"`         // 评论 id
+	Author    string    `bson:"author"`     // 作者
+	Title     string    `bson:"title"`      // 标题
+	Content   string    `bson:"content"`    // 内容
+	Rating    int       `bson:"rating"`     // 评分，1 - 5
+	Version   string    `bson:"version"`    // 评论时的应用版本
+	VoteCount int64     `bson:"vote_count"` // 认为有帮助的人数
+	Date      time.Time `bson:"date"`       // 评论时间
+}
+
+// 排序，默认最新
+func (o *IOSReviewOptions) sort() IOSReviewSort {
+	if o == nil || o.Sort == "" {
+		return IOSReviewSortRecent
+	}
+	return o.Sort
+}
+
+// 最多翻几页，不能超过接口的限制
+func (o *IOSReviewOptions) maxPages() int {
+	if o == nil || o.MaxPages <= 0 || o.MaxPages > maxIOSReviewPages {
+		return maxIOSReviewPages
+	}
+	return o.MaxPages
+}
+
+// 最多取多少条，0 为不限制
+func (o *IOSReviewOptions) limit() int {
+	if o == nil || o.Limit < 0 {
+		return 0
+	}
+	return o.Limit
+}
+
+// 获取 app store 的评论，country 为空时使用中国区，opts 为空时按最新排序取全部页
+func FetchIOSReviews(iosId, country string, opts *IOSReviewOptions) ([]*IOSReview, error) {
+	return FetchIOSReviewsContext(context.Background(), iosId, country, opts)
+}
+
+// 获取 app store 的评论，ctx 取消或超时时中断请求
+func FetchIOSReviewsContext(ctx context.Context, iosId, country string, opts *IOSReviewOptions) ([]*IOSReview, error) {
+	return DefaultClient.FetchIOSReviews(ctx, iosId, country, opts)
+}
+
+// 获取 app store 的评论，一页一页地取，取到空页或达到限制时停止，中途失败时返回已经取到的评论与错误
+func (c *Client) FetchIOSReviews(ctx context.Context, iosId, country string, opts *IOSReviewOptions) ([]*IOSReview, error) {
+	if strings.TrimSpace(iosId) == "" {
+		return nil, errors.New("iosId 不能为空")
+	}
+
+	if country == "" {
+		country = defaultAppStoreCountry
+	}
+
+	reviews := make([]*IOSReview, 0)
+
+	for page := 1; page <= opts.maxPages(); page++ {
+		// 已经取够了，不再请求下一页
+		if opts.limit() > 0 && len(reviews) >= opts.limit() {
+			break
+		}
+
+		json, err := c.getAppStoreReviewsPage(ctx, iosId, strings.ToLower(country), opts.sort(), page)
+		if err != nil {
+			return reviews, err
+		}
+
+		list := getAppStoreReviews(json)
+		if len(list) == 0 {
+			break
+		}
+
+		for _, review := range list {
+			if opts.limit() > 0 && len(reviews) >= opts.limit() {
+				return reviews, nil
+			}
+			reviews = append(reviews, review)
+		}
+	}
+
+	return reviews, nil
+}
+
+// 评论接口的一页数据
+func (c *Client) getAppStoreReviewsPage(ctx context.Context, iosId, country string, sort IOSReviewSort, page int) (*gjson.Result, error) {
+	u := "https://itunes.apple.com/" + country + "/rss/customerreviews/page=" + strconv.Itoa(page) + "/id=" + iosId + "/sortby=" + string(sort) + "/json"
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	json := gjson.ParseBytes(bytes)
+	return &json, nil
+}
+
+// 获取一页中的评论，只有一条时 entry 不是数组，没有评分的是应用信息，跳过
+func getAppStoreReviews(json *gjson.Result) []*IOSReview {
+	entry := json.Get("feed.entry")
+	if !entry.Exists() {
+		return nil
+	}
+
+	entries := entry.Array()
+	if entry.IsObject() {
+		entries = []gjson.Result{entry}
+	}
+
+	list := make([]*IOSReview, 0)
+
+	for _, v := range entries {
+		rating := v.Get("im:rating.label")
+		if !rating.Exists() {
+			continue
+		}
+
+		list = append(list, &IOSReview{
+			ID:        strings.TrimSpace(v.Get("id.label").String()),
+			Author:    strings.TrimSpace(v.Get("author.name.label").String()),
+			Title:     strings.TrimSpace(v.Get("title.label").String()),
+			Content:   strings.TrimSpace(v.Get("content.label").String()),
+			Rating:    int(rating.Int()),
+			Version:   strings.TrimSpace(v.Get("im:version.label").String()),
+			VoteCount: v.Get("im:voteCount.label").Int(),
+			Date:      normalizeDate(v.Get("updated.label").String()),
+		})
+	}
+
+	return list
+}
@@ -0,0 +1,115 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-31 11:03:56
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-31 11:03:56
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// 评论接口的一页，n 条评论
+func fakeIOSReviewsPage(page, n int) string {
+	entries := make([]string, 0)
+	for i := 0; i < n; i++ {
+		entries = append(entries, fmt.Sprintf(`{
+			"id": {"label": "%d-%d"}, "author": {"name": {"label": "user"}},
+			"title": {"label": "好用"}, "content": {"label": "很好用"},
+			"im:rating": {"label": "5"}, "im:version": {"label": "28.5.0"},
+			"im:voteCount": {"label": "3"}, "updated": {"label": "2023-07-20T01:23:45-07:00"}
+		}`, page, i))
+	}
+	return `{"feed": {"entry": [` + strings.Join(entries, ",") + `]}}`
+}
+
+func TestFetchIOSReviews(t *testing.T) {
+	urls := make([]string, 0)
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		urls = append(urls, req.URL.Path)
+
+		switch {
+		case strings.Contains(req.URL.Path, "page=1/"):
+			return 200, fakeIOSReviewsPage(1, 50)
+		case strings.Contains(req.URL.Path, "page=2/"):
+			return 200, fakeIOSReviewsPage(2, 20)
+		}
+		return 200, `{"feed": {}}`
+	})})
+
+	reviews, err := c.FetchIOSReviews(context.Background(), "123", "US", &IOSReviewOptions{Sort: IOSReviewSortHelpful})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != 70 {
+		t.Error("评论数量错了", len(reviews))
+	}
+
+	if urls[0] != "/us/rss/customerreviews/page=1/id=123/sortby=mosthelpful/json" {
+		t.Error("地址错了", urls[0])
+	}
+
+	// 空页之后不再请求
+	if len(urls) != 3 {
+		t.Error("请求次数错了", len(urls))
+	}
+
+	r := reviews[0]
+	if r.Rating != 5 || r.Version != "28.5.0" || r.Author != "user" || r.VoteCount != 3 || r.Date.IsZero() {
+		t.Error("评论取错了", r)
+	}
+}
+
+func TestFetchIOSReviewsLimit(t *testing.T) {
+	requests := 0
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		requests++
+		return 200, fakeIOSReviewsPage(1, 50)
+	})})
+
+	reviews, err := c.FetchIOSReviews(context.Background(), "123", "", &IOSReviewOptions{MaxPages: 2, Limit: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != 60 {
+		t.Error("limit 没生效", len(reviews))
+	}
+
+	// limit 正好是一页时不再请求下一页
+	requests = 0
+	reviews, _ = c.FetchIOSReviews(context.Background(), "123", "", &IOSReviewOptions{MaxPages: 2, Limit: 50})
+	if len(reviews) != 50 || requests != 1 {
+		t.Error("limit 取够后还在请求", len(reviews), requests)
+	}
+
+	reviews, _ = c.FetchIOSReviews(context.Background(), "123", "", &IOSReviewOptions{MaxPages: 2})
+	if len(reviews) != 100 {
+		t.Error("maxPages 没生效", len(reviews))
+	}
+}
+
+func TestGetAppStoreReviewsSingleEntry(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		return 200, `{"feed": {"entry": {"im:rating": {"label": "4"}, "title": {"label": "一条"}}}}`
+	})})
+
+	json, err := c.getAppStoreReviewsPage(context.Background(), "123", "cn", IOSReviewSortRecent, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviews := getAppStoreReviews(json)
+	if len(reviews) != 1 || reviews[0].Rating != 4 {
+		t.Error("单条评论取错了")
+	}
+}
@@ -8,16 +8,21 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 var IOS_APP_ID = "1563890743"
 
 func TestGetAppStoreName(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
@@ -30,7 +35,7 @@ func TestGetAppStoreName(t *testing.T) {
 }
 
 func TestGetAppStoreIcon(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
@@ -43,53 +48,53 @@ func TestGetAppStoreIcon(t *testing.T) {
 }
 
 func TestGetAppStoreBundleID(t *testing.T) {
-	id := getAppStoreBundleID(IOS_APP_ID)
+	id := DefaultClient.getAppStoreBundleID(context.Background(), IOS_APP_ID, nil)
 	if id == "" {
 		t.Error("bundle id 为空")
 	}
 }
 
 func TestGetAppStorePackageSize(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	size := getAppStorePackageSize(doc)
+	size := getAppStorePackageSize(doc, nil)
 	if size == "" {
 		t.Error("size 为空")
 	}
 }
 
 func TestGetAppStoreSupplier(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	supplier := getAppStoreSupplier(doc)
+	supplier := getAppStoreSupplier(doc, nil)
 	if supplier == "" {
 		t.Error("supplier 为空")
 	}
 }
 
 func TestGetAppStoreCategory(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	category := getAppStoreCategory(doc)
+	category := getAppStoreCategory(doc, nil)
 	if category == "" {
 		t.Error("category 为空")
 	}
 }
 
 func TestGetAppStoreDesc(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
@@ -103,26 +108,26 @@ func TestGetAppStoreDesc(t *testing.T) {
 }
 
 func TestGetAppStoreLanguage(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	language := getAppStoreLanguage(doc)
+	language := getAppStoreLanguage(doc, nil)
 	if language == "" {
 		t.Error("language 为空")
 	}
 }
 
 func TestGetAppStoreRate(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	rate := getAppStoreRate(doc)
+	rate := getAppStoreRate(doc, nil)
 	if rate == "" {
 		t.Error("rate 为空")
 	}
@@ -136,13 +141,13 @@ func TestGetAppStoreRate(t *testing.T) {
 }
 
 func TestGetAppStoreRateCount(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	rateCount := getAppStoreRateCount(doc)
+	rateCount := getAppStoreRateCount(doc, nil)
 	if rateCount == "" {
 		t.Error("rateCount 为空")
 	}
@@ -156,13 +161,13 @@ func TestGetAppStoreRateCount(t *testing.T) {
 
 // 更新时间和版本号有可能为空，不测试
 // func TestGetAppStoreLastUpdate(t *testing.T) {
-// 	doc, err := getAppStoreDoc(IOS_APP_ID)
+// 	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 // 	if err != nil {
 // 		t.Error(err)
 // 	}
 
-// 	version, update := getAppStoreLastUpdate(doc)
+// 	version, update := getAppStoreLastUpdate(doc, nil)
 // 	if version == "" {
 // 		t.Error("version 为空")
 // 	}
@@ -172,13 +177,13 @@ func TestGetAppStoreRateCount(t *testing.T) {
 // }
 
 func TestGetAppStorePrivacyPolicyUrl(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	url := getAppStorePrivacyPolicyUrl(doc)
+	url := getAppStorePrivacyPolicyUrl(doc, nil)
 	if url == "" {
 		t.Error("url 为空")
 	}
@@ -189,7 +194,7 @@ func TestGetAppStorePrivacyPolicyUrl(t *testing.T) {
 }
 
 func TestGetAppStoreDeveloperOtherApps(t *testing.T) {
-	doc, err := getAppStoreOtherAppsDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreOtherAppsDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
@@ -214,13 +219,13 @@ func TestGetAppStoreDeveloperOtherApps(t *testing.T) {
 }
 
 func TestGetAppStoreIAPList(t *testing.T) {
-	doc, err := getAppStoreDoc(IOS_APP_ID)
+	doc, err := DefaultClient.getAppStoreDoc(context.Background(), IOS_APP_ID, nil)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	list := getAppStoreIAPList(doc)
+	list := getAppStoreIAPList(doc, nil)
 	for _, a := range list {
 		if a.Name == "" {
 			t.Error("没找到 Name 字段")
@@ -230,3 +235,63 @@ func TestGetAppStoreIAPList(t *testing.T) {
 		}
 	}
 }
+
+func TestParseIOSDataContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseIOSDataContext(ctx, IOS_APP_ID)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("ctx 取消后请求没有中断")
+	}
+}
+
+func TestGetAppStoreRatingHistogram(t *testing.T) {
+	html := `
+	<section class="section">
+		<h2 class="section__headline">评分及评论</h2>
+		<div class="we-customer-ratings__stats">
+			<figure class="we-star-bar-graph">
+				<div class="we-star-bar-graph__row"><span class="we-star-bar-graph__stars we-star-bar-graph__stars--5"></span>
+					<div class="we-star-bar-graph__bar"><div class="we-star-bar-graph__bar__foreground-bar" style="width: 80%;"></div></div></div>
+				<div class="we-star-bar-graph__row"><span class="we-star-bar-graph__stars we-star-bar-graph__stars--4"></span>
+					<div class="we-star-bar-graph__bar"><div class="we-star-bar-graph__bar__foreground-bar" style="width: 15%;"></div></div></div>
+				<div class="we-star-bar-graph__row"><span class="we-star-bar-graph__stars we-star-bar-graph__stars--1"></span>
+					<div class="we-star-bar-graph__bar"><div class="we-star-bar-graph__bar__foreground-bar" style="width: 5%;"></div></div></div>
+			</figure>
+		</div>
+	</section>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := getAppStoreRatingHistogram(doc, nil, 1000)
+	if h.Star5 != 800 || h.Star4 != 150 || h.Star1 != 50 || h.Total() != 1000 {
+		t.Error("histogram 取错了", h)
+	}
+}
+
+func TestGetAppStoreLastUpdateRaw(t *testing.T) {
+	html := `<section class="whats-new"><p class="whats-new__latest__version">版本 28.5.0</p><time>2023年7月3日</time></section>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, update := getAppStoreLastUpdate(doc, nil)
+	if version != "28.5.0" || update != "2023年7月3日" {
+		t.Error("版本或原文取错了", version, update)
+	}
+
+	// 中国区按北京时间，其他区域按 UTC
+	if got := normalizeIOSDate(update, nil); !got.Equal(time.Date(2023, 7, 2, 16, 0, 0, 0, time.UTC)) {
+		t.Error("中国区时间取错了", got)
+	}
+
+	if got := normalizeIOSDate("Jul 3, 2023", &IOSOptions{Country: "us"}); !got.Equal(time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Error("美区时间取错了", got)
+	}
+}
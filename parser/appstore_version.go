@@ -0,0 +1,125 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-09 10:18:03
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-09 10:18:03
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// 版本历史中的一个版本
+type VersionEntry struct {
+	Version string    `bson:"version"` // 版本号
+	Date    time.Time `bson:"date"`    // 发布时间
+	Notes   string    `bson:"notes"`   // 更新说明
+}
+
+// shoebox 数据中各平台的 key，按顺序取第一个有版本历史的
+var appStoreVersionPlatforms = []string{"ios", "osx", "appletvos", "watchos", "xros"}
+
+// 获取 ios 应用的全部版本历史，从新到旧
+func FetchIOSVersionHistory(iosId string, opts ...*IOSOptions) ([]VersionEntry, error) {
+	return FetchIOSVersionHistoryContext(context.Background(), iosId, opts...)
+}
+
+// 获取 ios 应用的全部版本历史，ctx 取消或超时时中断请求
+func FetchIOSVersionHistoryContext(ctx context.Context, iosId string, opts ...*IOSOptions) ([]VersionEntry, error) {
+	return DefaultClient.FetchIOSVersionHistory(ctx, iosId, opts...)
+}
+
+// 获取 ios 应用的全部版本历史，从新到旧
+func (c *Client) FetchIOSVersionHistory(ctx context.Context, iosId string, opts ...*IOSOptions) ([]VersionEntry, error) {
+	opt := firstIOSOptions(opts)
+
+	if strings.TrimSpace(iosId) == "" {
+		return nil, errors.New("iosId 不能为空")
+	}
+
+	doc, err := c.getAppStoreDoc(ctx, iosId, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	history := getAppStoreVersionHistory(doc)
+	if len(history) == 0 {
+		return nil, layoutError("版本历史")
+	}
+
+	return history, nil
+}
+
+// 获取版本历史，优先使用页面中 shoebox 的数据，取不到时从版本历史弹窗中获取
+func getAppStoreVersionHistory(doc *goquery.Document) []VersionEntry {
+	if history := getAppStoreShoeboxVersionHistory(doc); len(history) > 0 {
+		return history
+	}
+
+	history := make([]VersionEntry, 0)
+
+	doc.Find(".version-history__item").Each(func(i int, s *goquery.Selection) {
+		version := strings.TrimSpace(s.Find(".version-history__item__version-number").Text())
+		if version == "" {
+			return
+		}
+
+		date := s.Find("time").AttrOr("datetime", "")
+		if date == "" {
+			date = s.Find("time").Text()
+		}
+
+		history = append(history, VersionEntry{
+			Version: version,
+			Date:    normalizeDate(date),
+			Notes:   strings.TrimSpace(s.Find(".version-history__item__release-notes").Text()),
+		})
+	})
+
+	return history
+}
+
+// 页面中 shoebox 的版本历史，shoebox 是 key 为接口地址、value 为接口返回内容（字符串）的 json
+func getAppStoreShoeboxVersionHistory(doc *goquery.Document) []VersionEntry {
+	text := doc.Find("script#shoebox-media-api-cache-apps").Text()
+	if text == "" {
+		return nil
+	}
+
+	history := make([]VersionEntry, 0)
+
+	gjson.Parse(text).ForEach(func(_, value gjson.Result) bool {
+		data := gjson.Parse(value.String())
+		attributes := data.Get("d.0.attributes.platformAttributes")
+
+		for _, platform := range appStoreVersionPlatforms {
+			list := attributes.Get(platform + ".versionHistory")
+			if !list.IsArray() {
+				continue
+			}
+
+			list.ForEach(func(_, v gjson.Result) bool {
+				history = append(history, VersionEntry{
+					Version: strings.TrimSpace(v.Get("versionDisplay").String()),
+					Date:    normalizeDate(v.Get("releaseDate").String()),
+					Notes:   strings.TrimSpace(v.Get("releaseNotes").String()),
+				})
+				return true
+			})
+
+			return false
+		}
+
+		return true
+	})
+
+	return history
+}
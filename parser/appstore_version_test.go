@@ -0,0 +1,89 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-09 11:26:51
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-09 11:26:51
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 带有 shoebox 版本历史的页面
+func fakeAppStoreShoeboxPage() string {
+	api := `{"d": [{"attributes": {"platformAttributes": {"ios": {"versionHistory": [
+		{"versionDisplay": "28.5.0", "releaseDate": "2023-07-20", "releaseNotes": "修复了一些问题"},
+		{"versionDisplay": "28.4.0", "releaseDate": "2023-07-10", "releaseNotes": "新功能"}
+	]}}}}]}`
+
+	shoebox, _ := json.Marshal(map[string]string{"/v1/catalog/cn/apps/123": api})
+
+	return `<html><script type="fastboot/shoebox" id="shoebox-media-api-cache-apps">` + string(shoebox) + `</script></html>`
+}
+
+func TestGetAppStoreVersionHistory(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fakeAppStoreShoeboxPage()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history := getAppStoreVersionHistory(doc)
+	if len(history) != 2 {
+		t.Fatal("版本数量错了", len(history))
+	}
+
+	if history[0].Version != "28.5.0" || history[0].Notes != "修复了一些问题" || history[0].Date.Day() != 20 {
+		t.Error("版本取错了", history[0])
+	}
+}
+
+func TestGetAppStoreVersionHistoryFromModal(t *testing.T) {
+	html := `<ul>
+		<li class="version-history__item">
+			<h4 class="version-history__item__version-number">28.5.0</h4>
+			<time datetime="2023-07-20T00:00:00.000Z">2023年7月20日</time>
+			<div class="version-history__item__release-notes"><p>修复了一些问题</p></div>
+		</li>
+	</ul>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history := getAppStoreVersionHistory(doc)
+	if len(history) != 1 || history[0].Version != "28.5.0" || history[0].Date.IsZero() {
+		t.Error("版本取错了", history)
+	}
+}
+
+func TestFetchIOSVersionHistory(t *testing.T) {
+	body := fakeAppStoreShoeboxPage()
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		return 200, body
+	})})
+
+	history, err := c.FetchIOSVersionHistory(context.Background(), "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 2 {
+		t.Error("版本数量错了", len(history))
+	}
+
+	body = "<html></html>"
+	if _, err := c.FetchIOSVersionHistory(context.Background(), "123"); !errors.Is(err, ErrLayoutChanged) {
+		t.Error("没有版本历史时应该返回 ErrLayoutChanged", err)
+	}
+}
@@ -0,0 +1,335 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-03 10:15:22
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-03 10:15:22
+ * @Description:
+ */
+package parser
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 下载的文件保存到哪里
+type AssetSink interface {
+	// 保存文件，name 为 sha256 加扩展名，返回保存后的位置
+	Save(name string, data []byte) (string, error)
+}
+
+// 保存到本地目录
+type dirSink struct {
+	dir string
+}
+
+// 保存到本地目录的 sink，目录不存在时自动创建
+func NewDirSink(dir string) AssetSink {
+	return dirSink{dir: dir}
+}
+
+func (s dirSink) Save(name string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(s.dir, name)
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// 写入 tar 包
+type tarSink struct {
+	mu sync.Mutex
+	tw *tar.Writer
+}
+
+// 把文件逐个写入 tar 包的 sink，返回的位置为包内的文件名，写完后由调用方 Close
+func NewTarSink(tw *tar.Writer) AssetSink {
+	return &tarSink{tw: tw}
+}
+
+func (s *tarSink) Save(name string, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+
+	if err := s.tw.WriteHeader(header); err != nil {
+		return "", err
+	}
+
+	if _, err := s.tw.Write(data); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// 写入 io.Writer 的 sink，内容为 tar 包，每个文件带有自己的 tar header
+type WriterSink struct {
+	tarSink
+}
+
+// 把文件打包成 tar 写入 w 的 sink，返回的位置为包内的文件名，写完后调用 Close 写入 tar 的结尾，w 本身不会被关闭
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{tarSink{tw: tar.NewWriter(w)}}
+}
+
+// 写入 tar 的结尾
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tw.Close()
+}
+
+// 单个文件的最大字节数，图标与截图都远小于它，超过时记录为下载失败
+const maxAssetSize = 32 << 20
+
+// 下载的一个文件
+type Asset struct {
+	URL    string `bson:"url"`    // 原地址
+	File   string `bson:"file"`   // 保存后的位置
+	SHA256 string `bson:"sha256"` // 内容的 sha256
+	Format string `bson:"format"` // 图片格式，例：png、jpeg、webp，不认识的格式为空
+	Width  int    `bson:"width"`  // 图片宽度
+	Height int    `bson:"height"` // 图片高度
+	Bytes  int64  `bson:"bytes"`  // 文件大小
+}
+
+// 下载结果，内容相同的地址只保存一份，对应同一个文件
+type AssetManifest struct {
+	Assets map[string]*Asset `bson:"assets"` // key 为原地址
+	Errors map[string]string `bson:"errors"` // 下载失败的地址与错误信息
+}
+
+// 下载图标、截图等文件，sink 为 NewDirSink、NewWriterSink 或 NewTarSink
+func DownloadAssets(urls []string, sink AssetSink) (*AssetManifest, error) {
+	return DownloadAssetsContext(context.Background(), urls, sink)
+}
+
+// 下载图标、截图等文件，ctx 取消或超时时中断请求
+func DownloadAssetsContext(ctx context.Context, urls []string, sink AssetSink) (*AssetManifest, error) {
+	return DefaultClient.DownloadAssets(ctx, urls, sink)
+}
+
+// 下载图标、截图等文件，单个地址失败或超过 32MB 时记录在 Errors 中，不影响其他地址
+func (c *Client) DownloadAssets(ctx context.Context, urls []string, sink AssetSink) (*AssetManifest, error) {
+	if sink == nil {
+		return nil, errors.New("sink 不能为空")
+	}
+
+	manifest := &AssetManifest{
+		Assets: make(map[string]*Asset),
+		Errors: make(map[string]string),
+	}
+
+	var (
+		mu    sync.Mutex
+		files = make(map[string]string) // sha256 与保存后的位置
+	)
+
+	tasks := make([]func(), 0)
+
+	for _, u := range dedupeStrings(urls) {
+		u := u
+
+		tasks = append(tasks, func() {
+			asset, data, err := c.getAsset(ctx, u)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err == nil {
+				// 内容相同的文件只保存一次
+				file, ok := files[asset.SHA256]
+				if !ok {
+					file, err = sink.Save(asset.SHA256+assetExt(asset.Format), data)
+					if err == nil {
+						files[asset.SHA256] = file
+					}
+				}
+				asset.File = file
+			}
+
+			if err != nil {
+				manifest.Errors[u] = err.Error()
+				return
+			}
+
+			manifest.Assets[u] = asset
+		})
+	}
+
+	c.parallel(tasks...)
+
+	return manifest, ctx.Err()
+}
+
+// 下载一个文件，返回文件信息与内容
+func (c *Client) getAsset(ctx context.Context, u string) (*Asset, []byte, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, nil, err
+	}
+
+	// 多读一个字节，用来判断是否超过限制
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(data) > maxAssetSize {
+		return nil, nil, fmt.Errorf("文件超过 %v 字节", maxAssetSize)
+	}
+
+	sum := sha256.Sum256(data)
+
+	asset := &Asset{
+		URL:    u,
+		SHA256: hex.EncodeToString(sum[:]),
+		Bytes:  int64(len(data)),
+	}
+	asset.Format, asset.Width, asset.Height = decodeImageConfig(data)
+
+	return asset, data, nil
+}
+
+// 图片的格式与尺寸，标准库不支持 webp，单独读文件头
+func decodeImageConfig(data []byte) (string, int, int) {
+	if format, w, h, ok := decodeWebPConfig(data); ok {
+		return format, w, h
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0
+	}
+
+	return format, config.Width, config.Height
+}
+
+// webp 的尺寸，支持 VP8、VP8L、VP8X 三种格式
+func decodeWebPConfig(data []byte) (string, int, int, bool) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return "", 0, 0, false
+	}
+
+	switch string(data[12:16]) {
+	case "VP8X":
+		w := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		h := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return "webp", w + 1, h + 1, true
+	case "VP8L":
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return "webp", int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, true
+	case "VP8 ":
+		w := binary.LittleEndian.Uint16(data[26:28]) & 0x3fff
+		h := binary.LittleEndian.Uint16(data[28:30]) & 0x3fff
+		return "webp", int(w), int(h), true
+	}
+
+	return "webp", 0, 0, true
+}
+
+// 图片格式对应的扩展名
+func assetExt(format string) string {
+	switch format {
+	case "":
+		return ""
+	case "jpeg":
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// 去掉空地址与重复的地址，保持原有顺序
+func dedupeStrings(list []string) []string {
+	found := make(map[string]bool)
+	result := make([]string, 0)
+
+	for _, v := range list {
+		if v == "" || found[v] {
+			continue
+		}
+		found[v] = true
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// 图标、同开发者应用的图标与截图的地址
+func (d *IOSData) AssetURLs() []string {
+	return appendAssetURLs([]string{d.IOSIcon}, d.IOSOtherApps, d.IOSMedia)
+}
+
+// 同开发者应用的图标与截图的地址
+func (d *HWData) AssetURLs() []string {
+	return appendAssetURLs(nil, d.HWOtherApps, d.HWMedia)
+}
+
+// 截图的地址
+func (d *MIData) AssetURLs() []string {
+	return appendAssetURLs(nil, nil, d.MIMedia)
+}
+
+// 截图的地址
+func (d *QQData) AssetURLs() []string {
+	return appendAssetURLs(nil, nil, d.QQMedia)
+}
+
+// 把应用图标、截图与视频封面的地址加到 list 后面
+func appendAssetURLs(list []string, apps []*App, media Media) []string {
+	for _, app := range apps {
+		list = append(list, app.Icon)
+	}
+
+	for _, v := range media.Screenshots {
+		list = append(list, v.URL)
+	}
+
+	for _, v := range media.Videos {
+		list = append(list, v.Poster)
+	}
+
+	return dedupeStrings(list)
+}
@@ -0,0 +1,200 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-03 11:40:17
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-03 11:40:17
+ * @Description:
+ */
+package parser
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// 生成一张 w x h 的 png
+func fakePNG(w, h int) string {
+	buf := new(bytes.Buffer)
+	png.Encode(buf, image.NewRGBA(image.Rect(0, 0, w, h)))
+	return buf.String()
+}
+
+func TestDownloadAssets(t *testing.T) {
+	icon := fakePNG(4, 3)
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		switch req.URL.Path {
+		case "/a.png", "/b.png":
+			return 200, icon
+		case "/c.png":
+			return 200, fakePNG(8, 8)
+		}
+		return 404, ""
+	})})
+
+	dir := t.TempDir()
+	urls := []string{"https://x/a.png", "https://x/b.png", "https://x/c.png", "https://x/d.png", "https://x/a.png", ""}
+
+	manifest, err := c.DownloadAssets(context.Background(), urls, NewDirSink(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest.Assets) != 3 || len(manifest.Errors) != 1 {
+		t.Fatal("数量错了", len(manifest.Assets), manifest.Errors)
+	}
+
+	a, b := manifest.Assets["https://x/a.png"], manifest.Assets["https://x/b.png"]
+	if a.File != b.File || a.SHA256 != b.SHA256 {
+		t.Error("内容相同的文件应该只保存一份")
+	}
+
+	if a.Format != "png" || a.Width != 4 || a.Height != 3 || a.Bytes != int64(len(icon)) {
+		t.Error("文件信息取错了", a)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Error("保存的文件数量错了", len(files))
+	}
+
+	data, _ := os.ReadFile(a.File)
+	if string(data) != icon {
+		t.Error("保存的内容错了")
+	}
+}
+
+func TestDownloadAssetsTarSink(t *testing.T) {
+	icon := fakePNG(2, 2)
+	shot := fakePNG(4, 8)
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if strings.HasSuffix(req.URL.Path, "shot.png") {
+			return 200, shot
+		}
+		return 200, icon
+	})})
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+
+	urls := []string{"https://x/a.png", "https://x/b.png", "https://x/shot.png"}
+	manifest, err := c.DownloadAssets(context.Background(), urls, NewTarSink(tw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 从 tar 包中按文件名读回内容
+	files := make(map[string]string)
+	tr := tar.NewReader(buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[header.Name] = string(data)
+	}
+
+	if len(files) != 2 {
+		t.Error("相同内容只应该写入一次", len(files))
+	}
+
+	if files[manifest.Assets["https://x/a.png"].File] != icon {
+		t.Error("图标内容读不回来")
+	}
+
+	if files[manifest.Assets["https://x/shot.png"].File] != shot {
+		t.Error("截图内容读不回来")
+	}
+
+	if manifest.Assets["https://x/a.png"].File != manifest.Assets["https://x/a.png"].SHA256+".png" {
+		t.Error("文件名错了")
+	}
+}
+
+func TestDecodeWebPConfig(t *testing.T) {
+	// VP8X 的文件头，宽 300 高 650
+	data := make([]byte, 30)
+	copy(data, "RIFF\x00\x00\x00\x00WEBPVP8X")
+	data[24], data[25] = byte(299&0xff), byte(299>>8)
+	data[27], data[28] = byte(649&0xff), byte(649>>8)
+
+	if format, w, h := decodeImageConfig(data); format != "webp" || w != 300 || h != 650 {
+		t.Error("webp 尺寸取错了", format, w, h)
+	}
+
+	if format, _, _ := decodeImageConfig([]byte("not an image")); format != "" {
+		t.Error("不认识的格式应该为空", format)
+	}
+}
+
+func TestDownloadAssetsWriterSink(t *testing.T) {
+	icon := fakePNG(2, 2)
+	shot := fakePNG(4, 8)
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if strings.HasSuffix(req.URL.Path, "shot.png") {
+			return 200, shot
+		}
+		return 200, icon
+	})})
+
+	buf := new(bytes.Buffer)
+	sink := NewWriterSink(buf)
+
+	manifest, err := c.DownloadAssets(context.Background(), []string{"https://x/a.png", "https://x/shot.png"}, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	tr := tar.NewReader(buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[header.Name] = string(data)
+	}
+
+	if files[manifest.Assets["https://x/a.png"].File] != icon || files[manifest.Assets["https://x/shot.png"].File] != shot {
+		t.Error("从 io.Writer 中读不回文件", len(files))
+	}
+}
+
+func TestDownloadAssetsTooLarge(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		return 200, strings.Repeat("x", maxAssetSize+1)
+	})})
+
+	manifest, err := c.DownloadAssets(context.Background(), []string{"https://x/big.png"}, NewDirSink(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := manifest.Errors["https://x/big.png"]; !ok || len(manifest.Assets) != 0 {
+		t.Error("超过大小限制的文件应该记录为失败")
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-27 14:05:18
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-27 14:05:18
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 百度手机助手，市场内的 id 为 docid
+type BaiduData struct {
+	BaiduExist         bool   `bson:"baidu_exist"`          // baidu 是否有
+	BaiduID            string `bson:"baidu_id"`             // baidu docid
+	BaiduPackageID     string `bson:"baidu_package_id"`     // baidu package id
+	BaiduName          string `bson:"baidu_name"`           // baidu 名称
+	BaiduSupplier      string `bson:"baidu_supplier"`       // baidu 开发者
+	BaiduDownloadCount string `bson:"baidu_download_count"` // baidu 下载量
+	BaiduLastVersion   string `bson:"baidu_last_version"`   // baidu 最新版本
+	BaiduLastUpdate    string `bson:"baidu_last_update"`    // baidu 最新版本时间
+
+	BaiduDownloadCountValue int64     `bson:"baidu_download_count_value"` // baidu 下载量，数字
+	BaiduLastUpdateTime     time.Time `bson:"baidu_last_update_time"`     // baidu 最新版本时间
+}
+
+// 包名
+func (d *BaiduData) PackageName() string {
+	return d.BaiduPackageID
+}
+
+// 获取百度手机助手数据，id 为 docid，可以用 Search 按名称获取
+func ParseBaiduData(id string) (*BaiduData, error) {
+	return ParseBaiduDataContext(context.Background(), id)
+}
+
+// 获取百度手机助手数据，ctx 取消或超时时中断请求
+func ParseBaiduDataContext(ctx context.Context, id string) (*BaiduData, error) {
+	return DefaultClient.ParseBaiduData(ctx, id)
+}
+
+// 获取百度手机助手数据
+func (c *Client) ParseBaiduData(ctx context.Context, id string) (*BaiduData, error) {
+	// 创建 baidu data 结构体
+	baiduData := new(BaiduData)
+
+	if strings.TrimSpace(id) == "" {
+		return baiduData, errors.New("id 不能为空")
+	}
+
+	doc, err := c.getBaiduDoc(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		// 下架的应用返回 404
+		return baiduData, nil
+	}
+	if err != nil {
+		return baiduData, err
+	}
+
+	baiduData.BaiduExist = getBaiduExist(doc)
+	if baiduData.BaiduExist {
+		baiduData.BaiduID = id
+		baiduData.BaiduPackageID = getBaiduPackageID(doc)
+		baiduData.BaiduName = getBaiduName(doc)
+		baiduData.BaiduSupplier = getBaiduSupplier(doc)
+		baiduData.BaiduDownloadCount = getBaiduDownloadCount(doc)
+		baiduData.BaiduLastVersion = getBaiduLastVersion(doc)
+		baiduData.BaiduLastUpdate = getBaiduLastUpdate(doc)
+		baiduData.BaiduDownloadCountValue = normalizeCount(baiduData.BaiduDownloadCount)
+		baiduData.BaiduLastUpdateTime = normalizeLocalDate(baiduData.BaiduLastUpdate)
+
+		if baiduData.BaiduName == "" {
+			return baiduData, layoutError("名称")
+		}
+	}
+
+	return baiduData, nil
+}
+
+// 百度手机助手
+type baiduStore struct{}
+
+func (baiduStore) ID() string {
+	return StoreBaidu
+}
+
+func (baiduStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	baiduData, err := c.ParseBaiduData(ctx, id)
+	if err != nil {
+		return baiduData, err
+	}
+
+	if !baiduData.BaiduExist {
+		return baiduData, ErrNotFound
+	}
+
+	return baiduData, nil
+}
+
+func (baiduStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	doc, err := c.getBaiduSearchDoc(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return getBaiduSearchID(doc, name), nil
+}
+
+func (baiduStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	baiduData, err := c.ParseBaiduData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return baiduData.BaiduExist, nil
+}
+
+func (c *Client) getBaiduDoc(ctx context.Context, id string) (*goquery.Document, error) {
+	u := "https://shouji.baidu.com/software/" + url.PathEscape(id) + ".html"
+	return c.getBaiduPage(ctx, u)
+}
+
+// 百度手机助手搜索结果页面的 doc
+func (c *Client) getBaiduSearchDoc(ctx context.Context, name string) (*goquery.Document, error) {
+	params := url.Values{}
+	params.Add("wd", name)
+	params.Add("data_type", "app")
+
+	u := "https://shouji.baidu.com/s?" + params.Encode()
+	return c.getBaiduPage(ctx, u)
+}
+
+func (c *Client) getBaiduPage(ctx context.Context, u string) (*goquery.Document, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 搜索结果中详情页的链接，例：/software/33472845.html
+var baiduDocIDReg = regexp.MustCompile(`/software/([0-9]+)\.html`)
+
+// 从搜索结果中获取 docid，只取第一个结果，名称不匹配时返回空
+func getBaiduSearchID(doc *goquery.Document, name string) string {
+	node := doc.Find(".app-box .info .top a").First()
+	findName := strings.TrimSpace(node.Text())
+
+	// 判断名称是否匹配
+	if findName == "" || !strings.Contains(name, findName) {
+		return ""
+	}
+
+	m := baiduDocIDReg.FindStringSubmatch(node.AttrOr("href", ""))
+	if m == nil {
+		return ""
+	}
+
+	return m[1]
+}
+
+// 判断是否在百度手机助手上架
+func getBaiduExist(doc *goquery.Document) bool {
+	node := doc.Find(".app-intro .app-name")
+	return node.Length() > 0
+}
+
+// 获取包名，在下载按钮上，取不到时返回空
+func getBaiduPackageID(doc *goquery.Document) string {
+	node := doc.Find(".area-download [data_package]").First()
+	return strings.TrimSpace(node.AttrOr("data_package", ""))
+}
+
+// 获取名称
+func getBaiduName(doc *goquery.Document) string {
+	node := doc.Find(".app-intro .app-name span").First()
+	return strings.TrimSpace(node.Text())
+}
+
+// 获取开发者
+func getBaiduSupplier(doc *goquery.Document) string {
+	return getBaiduAttr(doc, "开发者")
+}
+
+// 获取下载量，例：1亿
+func getBaiduDownloadCount(doc *goquery.Document) string {
+	return getBaiduAttr(doc, "下载次数")
+}
+
+// 获取最新版本号
+func getBaiduLastVersion(doc *goquery.Document) string {
+	return getBaiduAttr(doc, "版本")
+}
+
+// 获取最新更新时间
+func getBaiduLastUpdate(doc *goquery.Document) string {
+	return getBaiduAttr(doc, "更新时间")
+}
+
+// 获取详细信息中的一项，例：<span class="version">版本: 26.8.0</span>
+func getBaiduAttr(doc *goquery.Document, label string) string {
+	value := ""
+
+	doc.Find(".app-intro .detail span, .app-intro .origin-wrap span").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		text := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(text, label) {
+			return true
+		}
+
+		value = strings.TrimPrefix(text, label)
+		value = strings.TrimLeft(value, "：: ")
+		return false
+	})
+
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,71 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-27 14:47:03
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-27 14:47:03
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var BAIDU_APP_ID = "33472845"
+
+func TestGetBaiduExist(t *testing.T) {
+	doc, err := DefaultClient.getBaiduDoc(context.Background(), BAIDU_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !getBaiduExist(doc) {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetBaiduName(t *testing.T) {
+	doc, err := DefaultClient.getBaiduDoc(context.Background(), BAIDU_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getBaiduName(doc)
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetBaiduLastVersion(t *testing.T) {
+	doc, err := DefaultClient.getBaiduDoc(context.Background(), BAIDU_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getBaiduLastVersion(doc)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetBaiduDownloadCount(t *testing.T) {
+	doc, err := DefaultClient.getBaiduDoc(context.Background(), BAIDU_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	count := getBaiduDownloadCount(doc)
+
+	if normalizeCount(count) == 0 {
+		t.Error("count 取错了")
+	}
+}
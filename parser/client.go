@@ -0,0 +1,115 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-11 14:20:05
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-11 14:20:05
+ * @Description:
+ */
+package parser
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// 默认的并发请求数
+const defaultWorkers = 4
+
+// 客户端配置
+type Options struct {
+	Timeout   time.Duration     // 单个请求的超时时间，0 表示不限制
+	Proxy     string            // 代理地址，例：http://127.0.0.1:8080，为空时读取环境变量
+	TLSConfig *tls.Config       // tls 配置
+	Transport http.RoundTripper // 自定义 transport，设置后忽略 Proxy 与 TLSConfig
+	Workers   int               // 同时进行的请求数，0 表示使用默认值
+}
+
+// 客户端，各市场的请求共用同一个 http client
+type Client struct {
+	httpClient *http.Client
+	workers    int
+}
+
+// 默认客户端，包级别的 Parse* 函数都使用它
+var DefaultClient = &Client{httpClient: &http.Client{}}
+
+// 根据配置创建客户端
+func NewClient(opts *Options) (*Client, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+
+	transport := opts.Transport
+	if transport == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+
+		if opts.Proxy != "" {
+			proxy, err := url.Parse(opts.Proxy)
+			if err != nil {
+				return nil, err
+			}
+			t.Proxy = http.ProxyURL(proxy)
+		}
+
+		if opts.TLSConfig != nil {
+			t.TLSClientConfig = opts.TLSConfig
+		}
+
+		transport = t
+	}
+
+	c := NewClientWithHTTPClient(&http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	})
+	c.workers = opts.Workers
+
+	return c, nil
+}
+
+// 使用已有的 http client 创建客户端
+func NewClientWithHTTPClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	return &Client{httpClient: httpClient}
+}
+
+// 客户端使用的 http client，自定义市场可以用它发请求，零值的 Client 使用 http.DefaultClient
+func (c *Client) HTTPClient() *http.Client {
+	if c.httpClient == nil {
+		return http.DefaultClient
+	}
+	return c.httpClient
+}
+
+// 并发执行 tasks，同时最多执行 workers 个，全部执行完后返回
+func (c *Client) parallel(tasks ...func()) {
+	workers := c.workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	sem := make(chan struct{}, workers)
+	wg := sync.WaitGroup{}
+
+	for _, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(task func()) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			task()
+		}(task)
+	}
+
+	wg.Wait()
+}
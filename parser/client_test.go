@@ -0,0 +1,127 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-11 15:02:44
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-11 15:02:44
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 返回固定内容的 transport
+type fakeTransport struct {
+	status int
+	body   string
+}
+
+func (t *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: t.status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestNewClientTransport(t *testing.T) {
+	c, err := NewClient(&Options{
+		Transport: &fakeTransport{
+			status: 200,
+			body:   `<div class="bigimg-scroll-title"></div><div class="intro-titles"><h3>抖音</h3></div>`,
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	miData, err := c.ParseMIData(context.Background(), MI_APP_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !miData.MIExist {
+		t.Error("exist 取错了")
+	}
+
+	if miData.MIName != "抖音" {
+		t.Error("name 取错了")
+	}
+}
+
+func TestNewClientProxy(t *testing.T) {
+	_, err := NewClient(&Options{Proxy: "://bad"})
+	if err == nil {
+		t.Error("错误的代理地址没有报错")
+	}
+}
+
+func TestClientParallel(t *testing.T) {
+	c, _ := NewClient(&Options{Workers: 2})
+
+	mu := sync.Mutex{}
+	running, max, done := 0, 0, 0
+
+	tasks := make([]func(), 0)
+	for i := 0; i < 10; i++ {
+		tasks = append(tasks, func() {
+			mu.Lock()
+			running++
+			if running > max {
+				max = running
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			running--
+			done++
+			mu.Unlock()
+		})
+	}
+
+	c.parallel(tasks...)
+
+	if done != 10 {
+		t.Error("有任务没有执行")
+	}
+
+	if max > 2 {
+		t.Errorf("同时执行了 %v 个任务", max)
+	}
+}
+
+// 根据请求返回内容的 transport
+type transportFunc func(req *http.Request) (int, string)
+
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	status, body := f(req)
+	return (&fakeTransport{status: status, body: body}).RoundTrip(req)
+}
+
+func TestZeroValueClient(t *testing.T) {
+	// 零值的 Client 使用 http.DefaultClient，替换默认 transport 避免真的发请求
+	transport := http.DefaultTransport
+	http.DefaultTransport = &fakeTransport{status: 404}
+	defer func() { http.DefaultTransport = transport }()
+
+	c := &Client{}
+	if c.HTTPClient() != http.DefaultClient {
+		t.Error("零值的 Client 应该使用 http.DefaultClient")
+	}
+
+	wdjData, err := c.ParseWDJData(context.Background(), "com.ss.android.ugc.aweme")
+	if err != nil || wdjData.WDJExist {
+		t.Error("零值的 Client 请求失败", err)
+	}
+}
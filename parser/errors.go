@@ -0,0 +1,64 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-13 10:31:27
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-13 10:31:27
+ * @Description:
+ */
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 可以用 errors.Is 判断的错误类型
+var (
+	ErrNotFound      = errors.New("应用未上架")
+	ErrBlocked       = errors.New("请求被拦截")
+	ErrLayoutChanged = errors.New("页面结构变化，解析失败")
+
+	ErrSearchUnsupported = errors.New("市场不支持按名称搜索")
+	ErrUnsupportedImage  = errors.New("不支持的图片格式")
+)
+
+// 请求返回了非 200 的状态
+type HTTPError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("返回状态错误 %v", e.StatusCode)
+}
+
+// 404 视为 ErrNotFound，403、429 视为 ErrBlocked
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrBlocked:
+		return e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// 检查返回状态，非 200 时返回 *HTTPError
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	e := &HTTPError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		e.URL = resp.Request.URL.String()
+	}
+
+	return e
+}
+
+// 解析失败的错误，field 为没有取到的字段
+func layoutError(field string) error {
+	return fmt.Errorf("%w: 没找到 %v", ErrLayoutChanged, field)
+}
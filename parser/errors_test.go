@@ -0,0 +1,56 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-13 11:02:16
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-13 11:02:16
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHTTPErrorIs(t *testing.T) {
+	if !errors.Is(&HTTPError{StatusCode: 404}, ErrNotFound) {
+		t.Error("404 应该是 ErrNotFound")
+	}
+
+	if !errors.Is(fmt.Errorf("wrap: %w", &HTTPError{StatusCode: 429}), ErrBlocked) {
+		t.Error("429 应该是 ErrBlocked")
+	}
+
+	if errors.Is(&HTTPError{StatusCode: 500}, ErrBlocked) {
+		t.Error("500 不应该是 ErrBlocked")
+	}
+}
+
+func TestNewMarketResult(t *testing.T) {
+	cases := map[error]MarketStatus{
+		nil:                         MarketOK,
+		ErrNotFound:                 MarketNotFound,
+		&HTTPError{StatusCode: 404}: MarketNotFound,
+		&HTTPError{StatusCode: 403}: MarketHTTPError,
+		layoutError("名称"):           MarketParseError,
+		context.DeadlineExceeded:    MarketHTTPError,
+	}
+
+	for err, status := range cases {
+		result := newMarketResult(err)
+		if result.Status != status {
+			t.Errorf("%v 的状态应该是 %v，实际是 %v", err, status, result.Status)
+		}
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: &fakeTransport{status: 403}})
+
+	_, err := c.getMIDoc(context.Background(), MI_APP_ID)
+	if !errors.Is(err, ErrBlocked) {
+		t.Error("403 应该是 ErrBlocked")
+	}
+}
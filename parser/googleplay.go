@@ -0,0 +1,379 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-20 10:48:15
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-20 10:48:15
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// google play 默认的区域与语言
+const (
+	defaultGPCountry = "us"
+	defaultGPLang    = "en"
+)
+
+// google play 市场
+type GPData struct {
+	GPExist            bool   `bson:"gp_exist"`              // gp 是否有
+	GPPackageID        string `bson:"gp_package_id"`         // gp package id
+	GPCountry          string `bson:"gp_country"`            // gp 区域
+	GPName             string `bson:"gp_name"`               // gp 名称
+	GPDeveloper        string `bson:"gp_developer"`          // gp 开发者
+	GPRate             string `bson:"gp_rate"`               // gp 评分
+	GPRateCount        string `bson:"gp_rate_count"`         // gp 评价数
+	GPInstalls         string `bson:"gp_installs"`           // gp 安装量区间，例：1,000,000+
+	GPLastVersion      string `bson:"gp_last_version"`       // gp 最新版本
+	GPLastUpdate       string `bson:"gp_last_update"`        // gp 最新版本时间
+	GPPackageSize      string `bson:"gp_package_size"`       // gp 包大小，网页上大多不展示，取不到时为空
+	GPContentRating    string `bson:"gp_content_rating"`     // gp 内容分级
+	GPPrivacyPolicyUrl string `bson:"gp_privacy_policy_url"` // gp 隐私政策地址
+	GPOtherApps        []*App `bson:"gp_other_apps"`         // gp 同开发者的app
+
+	GPRateValue      float64   `bson:"gp_rate_value"`       // gp 评分，数字
+	GPRateCountValue int64     `bson:"gp_rate_count_value"` // gp 评价数，数字
+	GPInstallsValue  int64     `bson:"gp_installs_value"`   // gp 安装量区间的下限
+	GPPackageBytes   int64     `bson:"gp_package_bytes"`    // gp 包大小，字节数
+	GPLastUpdateTime time.Time `bson:"gp_last_update_time"` // gp 最新版本时间
+}
+
+// 包名
+func (d *GPData) PackageName() string {
+	return d.GPPackageID
+}
+
+// 获取 google play 数据，country 为区域（例：us），lang 为语言（例：en），为空时使用 us、en
+func ParseGPData(pkgId, country, lang string) (*GPData, error) {
+	return ParseGPDataContext(context.Background(), pkgId, country, lang)
+}
+
+// 获取 google play 数据，ctx 取消或超时时中断请求
+func ParseGPDataContext(ctx context.Context, pkgId, country, lang string) (*GPData, error) {
+	return DefaultClient.ParseGPData(ctx, pkgId, country, lang)
+}
+
+// 获取 google play 数据
+func (c *Client) ParseGPData(ctx context.Context, pkgId, country, lang string) (*GPData, error) {
+	// 创建 gp data 结构体
+	gpData := new(GPData)
+
+	if strings.TrimSpace(pkgId) == "" {
+		return gpData, errors.New("pkgId 不能为空")
+	}
+
+	if country == "" {
+		country = defaultGPCountry
+	}
+
+	if lang == "" {
+		lang = defaultGPLang
+	}
+
+	doc, err := c.getGPDoc(ctx, pkgId, country, lang)
+	if errors.Is(err, ErrNotFound) {
+		// 未上架的应用返回 404
+		return gpData, nil
+	}
+	if err != nil {
+		return gpData, err
+	}
+
+	gpData.GPExist = true
+	gpData.GPPackageID = pkgId
+	gpData.GPCountry = country
+
+	ld := getGPLdJson(doc)
+	ds := getGPDataset(doc)
+
+	gpData.GPName = getGPName(ld)
+	gpData.GPDeveloper = getGPDeveloper(ld)
+	gpData.GPRate = getGPRate(ld)
+	gpData.GPRateCount = getGPRateCount(ld)
+	gpData.GPContentRating = getGPContentRating(ld)
+	gpData.GPInstalls = getGPInstalls(ds)
+	gpData.GPLastVersion = getGPLastVersion(ds)
+	gpData.GPLastUpdate = getGPLastUpdate(ds)
+	gpData.GPPackageSize = getGPPackageSize(ds)
+	gpData.GPPrivacyPolicyUrl = getGPPrivacyPolicyUrl(ds)
+
+	// 同开发者的其他应用，页面失败时留空
+	devDoc, err := c.getGPDeveloperDoc(ctx, getGPDeveloperLink(ds), country, lang)
+	if err == nil {
+		gpData.GPOtherApps = getGPOtherApps(devDoc, pkgId)
+	}
+
+	// 数字与时间
+	gpData.GPRateValue = normalizeRate(gpData.GPRate)
+	gpData.GPRateCountValue = normalizeCount(gpData.GPRateCount)
+	gpData.GPInstallsValue = normalizeCount(gpData.GPInstalls)
+	gpData.GPPackageBytes = normalizeSize(gpData.GPPackageSize)
+	gpData.GPLastUpdateTime = getGPLastUpdateTime(ds)
+
+	if gpData.GPName == "" {
+		return gpData, layoutError("名称")
+	}
+
+	return gpData, nil
+}
+
+// google play 市场，搜索结果没有稳定的结构，不支持按名称搜索
+type gpStore struct {
+	unsearchable
+
+	country string
+	lang    string
+}
+
+// 创建指定区域与语言的 google play 市场，可以用 RegisterStore 替换默认的 us、en
+func NewGPStore(country, lang string) Store {
+	return gpStore{country: country, lang: lang}
+}
+
+func (gpStore) ID() string {
+	return StoreGP
+}
+
+func (s gpStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	gpData, err := c.ParseGPData(ctx, id, s.country, s.lang)
+	if err != nil {
+		return gpData, err
+	}
+
+	if !gpData.GPExist {
+		return gpData, ErrNotFound
+	}
+
+	return gpData, nil
+}
+
+func (s gpStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	gpData, err := c.ParseGPData(ctx, id, s.country, s.lang)
+	if err != nil {
+		return false, err
+	}
+
+	return gpData.GPExist, nil
+}
+
+func (gpStore) UsesPackageName() bool {
+	return true
+}
+
+func (c *Client) getGPDoc(ctx context.Context, id, country, lang string) (*goquery.Document, error) {
+	params := url.Values{}
+	params.Add("id", id)
+	params.Add("hl", lang)
+	params.Add("gl", country)
+
+	u := "https://play.google.com/store/apps/details?" + params.Encode()
+
+	return c.getGPPage(ctx, u)
+}
+
+// 开发者页面，link 为详情页面中开发者的链接，例：/store/apps/developer?id=TikTok+Pte.+Ltd.
+func (c *Client) getGPDeveloperDoc(ctx context.Context, link, country, lang string) (*goquery.Document, error) {
+	if link == "" {
+		return nil, ErrNotFound
+	}
+
+	u, err := url.Parse("https://play.google.com" + link)
+	if err != nil {
+		return nil, err
+	}
+
+	params := u.Query()
+	params.Set("hl", lang)
+	params.Set("gl", country)
+	u.RawQuery = params.Encode()
+
+	return c.getGPPage(ctx, u.String())
+}
+
+func (c *Client) getGPPage(ctx context.Context, u string) (*goquery.Document, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 页面中的 ld+json 数据，包含名称、开发者、评分等
+func getGPLdJson(doc *goquery.Document) *gjson.Result {
+	text := doc.Find("script[type='application/ld+json']").First().Text()
+	json := gjson.Parse(text)
+	return &json
+}
+
+// 匹配页面中 ds:5 的数据，版本、更新时间、安装量等只在这里有
+var gpDatasetReg = regexp.MustCompile(`(?s)AF_initDataCallback\(\{key: 'ds:5',.*?data:(.*?), sideChannel: \{\}\}\);`)
+
+// 页面中 ds:5 的数据
+func getGPDataset(doc *goquery.Document) *gjson.Result {
+	data := ""
+
+	doc.Find("script").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		m := gpDatasetReg.FindStringSubmatch(s.Text())
+		if m != nil {
+			data = m[1]
+			return false
+		}
+		return true
+	})
+
+	json := gjson.Parse(data)
+	return &json
+}
+
+// 获取名称
+func getGPName(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("name").String())
+}
+
+// 获取开发者
+func getGPDeveloper(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("author.name").String())
+}
+
+// 获取评分
+func getGPRate(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("aggregateRating.ratingValue").String())
+}
+
+// 获取评价数量
+func getGPRateCount(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("aggregateRating.ratingCount").String())
+}
+
+// 获取内容分级
+func getGPContentRating(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("contentRating").String())
+}
+
+// 获取安装量
+func getGPInstalls(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("1.2.13.0").String())
+}
+
+// 获取最新版本号
+func getGPLastVersion(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("1.2.140.0.0.0").String())
+}
+
+// 获取最新更新时间，页面上展示的文案
+func getGPLastUpdate(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("1.2.145.0.0").String())
+}
+
+// 获取最新更新时间，优先使用时间戳
+func getGPLastUpdateTime(json *gjson.Result) time.Time {
+	ts := json.Get("1.2.145.0.1.0").Int()
+	if ts > 0 {
+		return time.Unix(ts, 0).UTC()
+	}
+
+	return normalizeDate(getGPLastUpdate(json))
+}
+
+// 包大小的格式，例：25M、1.2 GB
+var gpSizeReg = regexp.MustCompile(`^[0-9.,]+\s?[KMG]B?$`)
+
+// 获取包大小，位置不固定，找到第一个像包大小的字符串
+func getGPPackageSize(json *gjson.Result) string {
+	size := ""
+
+	var walk func(v gjson.Result) bool
+	walk = func(v gjson.Result) bool {
+		if v.IsArray() || v.IsObject() {
+			v.ForEach(func(_, value gjson.Result) bool {
+				return walk(value)
+			})
+			return size == ""
+		}
+
+		if v.Type == gjson.String && gpSizeReg.MatchString(v.String()) {
+			size = v.String()
+			return false
+		}
+
+		return true
+	}
+
+	walk(json.Get("1.2"))
+
+	return size
+}
+
+// 获取隐私政策网址
+func getGPPrivacyPolicyUrl(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("1.2.99.0.5.2").String())
+}
+
+// 获取开发者页面的链接
+func getGPDeveloperLink(json *gjson.Result) string {
+	return strings.TrimSpace(json.Get("1.2.68.1.4.2").String())
+}
+
+// 获取 同开发者的其他应用
+func getGPOtherApps(doc *goquery.Document, pkgId string) []*App {
+	items := doc.Find("a[href^='/store/apps/details?id=']")
+	if items.Length() == 0 {
+		return nil
+	}
+
+	apps := make([]*App, 0)
+	found := make(map[string]bool)
+
+	items.Each(func(i int, s *goquery.Selection) {
+		href, err := url.Parse(s.AttrOr("href", ""))
+		if err != nil {
+			return
+		}
+
+		id := href.Query().Get("id")
+		if id == "" || id == pkgId || found[id] {
+			return
+		}
+		found[id] = true
+
+		apps = append(apps, &App{
+			ID:   id,
+			Name: strings.TrimSpace(s.Find("span").First().Text()),
+			Icon: s.Find("img").AttrOr("src", ""),
+		})
+	})
+
+	return apps
+}
@@ -0,0 +1,131 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-20 11:36:02
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-20 11:36:02
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var GP_APP_ID = "com.zhiliaoapp.musically"
+
+func TestGetGPName(t *testing.T) {
+	doc, err := DefaultClient.getGPDoc(context.Background(), GP_APP_ID, "us", "en")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getGPName(getGPLdJson(doc))
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetGPLastVersion(t *testing.T) {
+	doc, err := DefaultClient.getGPDoc(context.Background(), GP_APP_ID, "us", "en")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getGPLastVersion(getGPDataset(doc))
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetGPExist(t *testing.T) {
+	gpData, err := ParseGPData(GP_APP_ID+"fake", "us", "en")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gpData.GPExist {
+		t.Error("exist 取错了")
+	}
+}
+
+// ds:5 中应用详情的位置数组，按下标放入裁剪过的真实数据，其余位置为 null
+func fakeGPDetail() string {
+	fields := map[int]string{
+		0:   `[["TikTok"]]`,
+		13:  `["1,000,000,000+","1000000000",3117236066,"3B+"]`,
+		37:  `["TikTok Pte. Ltd.",["https://www.tiktok.com"]]`,
+		68:  `["TikTok Pte. Ltd.",[null,null,null,null,[null,null,"https://play.google.com/store/apps/dev?id=7586468483017491519"]]]`,
+		99:  `[[null,null,null,null,null,[null,null,"https://example.com/privacy"]]]`,
+		112: `[["30.5.4","152M"]]`,
+		140: `[[["30.5.4"]],[[[33,"13"]],[[[21,"5.0"]]]]]`,
+		145: `[["Jul 3, 2023",[1688342400,0]]]`,
+	}
+
+	list := make([]string, 146)
+	for i := range list {
+		list[i] = "null"
+		if v, ok := fields[i]; ok {
+			list[i] = v
+		}
+	}
+
+	return "[" + strings.Join(list, ",") + "]"
+}
+
+func TestGetGPDataset(t *testing.T) {
+	html := `<script type="application/ld+json">{"name": "TikTok", "author": {"name": "TikTok Pte. Ltd."},
+		"aggregateRating": {"ratingValue": "4.4", "ratingCount": "58000000"}, "contentRating": "Teen"}</script>
+	<script>AF_initDataCallback({key: 'ds:4', hash: '3', data:[[null,"decoy"]], sideChannel: {}});</script>
+	<script>AF_initDataCallback({key: 'ds:5', hash: '7', data:[null,[null,null,` + fakeGPDetail() + `]], sideChannel: {}});</script>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ld := getGPLdJson(doc)
+	if getGPName(ld) != "TikTok" || getGPDeveloper(ld) != "TikTok Pte. Ltd." || getGPRate(ld) != "4.4" {
+		t.Error("ld+json 取错了")
+	}
+
+	ds := getGPDataset(doc)
+	if getGPInstalls(ds) != "1,000,000,000+" {
+		t.Error("installs 取错了")
+	}
+
+	if getGPLastVersion(ds) != "30.5.4" {
+		t.Error("version 取错了")
+	}
+
+	if getGPLastUpdateTime(ds).Unix() != 1688342400 {
+		t.Error("update 取错了")
+	}
+
+	if getGPPackageSize(ds) != "152M" {
+		t.Error("size 取错了")
+	}
+
+	if getGPPrivacyPolicyUrl(ds) != "https://example.com/privacy" {
+		t.Error("url 取错了")
+	}
+
+	if getGPDeveloperLink(ds) != "https://play.google.com/store/apps/dev?id=7586468483017491519" {
+		t.Error("开发者链接取错了")
+	}
+
+	// ds:5 是位置数组，字段都按下标取
+	if !ds.Get("1.2").IsArray() {
+		t.Error("ds:5 应该是数组")
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-25 10:40:51
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-25 10:40:51
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// 荣耀应用市场，和华为市场是两套数据
+type HonorData struct {
+	HonorExist            bool   `bson:"honor_exist"`              // honor 是否有
+	HonorPackageID        string `bson:"honor_package_id"`         // honor package id
+	HonorName             string `bson:"honor_name"`               // honor 名称
+	HonorSupplier         string `bson:"honor_supplier"`           // honor 开发者
+	HonorRate             string `bson:"honor_rate"`               // honor 评分
+	HonorLastVersion      string `bson:"honor_last_version"`       // honor 最新版本
+	HonorLastUpdate       string `bson:"honor_last_update"`        // honor 最新版本时间
+	HonorPackageSize      string `bson:"honor_package_size"`       // honor 包大小，单位字节
+	HonorPrivacyPolicyUrl string `bson:"honor_privacy_policy_url"` // honor 隐私政策地址
+
+	HonorRateValue      float64   `bson:"honor_rate_value"`       // honor 评分，数字
+	HonorPackageBytes   int64     `bson:"honor_package_bytes"`    // honor 包大小，字节数
+	HonorLastUpdateTime time.Time `bson:"honor_last_update_time"` // honor 最新版本时间
+}
+
+// 包名
+func (d *HonorData) PackageName() string {
+	return d.HonorPackageID
+}
+
+// 获取荣耀应用市场数据
+func ParseHonorData(pkgId string) (*HonorData, error) {
+	return ParseHonorDataContext(context.Background(), pkgId)
+}
+
+// 获取荣耀应用市场数据，ctx 取消或超时时中断请求
+func ParseHonorDataContext(ctx context.Context, pkgId string) (*HonorData, error) {
+	return DefaultClient.ParseHonorData(ctx, pkgId)
+}
+
+// 获取荣耀应用市场数据
+func (c *Client) ParseHonorData(ctx context.Context, pkgId string) (*HonorData, error) {
+	// 创建 honor data 结构体
+	honorData := new(HonorData)
+
+	if strings.TrimSpace(pkgId) == "" {
+		return honorData, errors.New("pkgId 不能为空")
+	}
+
+	json, err := c.getHonorAppData(ctx, pkgId)
+	if err != nil {
+		return honorData, err
+	}
+
+	honorData.HonorExist = getHonorExist(json)
+	if honorData.HonorExist {
+		honorData.HonorPackageID = pkgId
+		honorData.HonorName = getHonorName(json)
+		honorData.HonorSupplier = getHonorSupplier(json)
+		honorData.HonorRate = getHonorRate(json)
+		honorData.HonorLastVersion = getHonorLastVersion(json)
+		honorData.HonorLastUpdate = getHonorLastUpdate(json)
+		honorData.HonorPackageSize = getHonorPackageSize(json)
+		honorData.HonorPrivacyPolicyUrl = getHonorPrivacyPolicyUrl(json)
+		honorData.HonorRateValue = normalizeRate(honorData.HonorRate)
+		honorData.HonorPackageBytes = normalizeSize(honorData.HonorPackageSize)
+		honorData.HonorLastUpdateTime = normalizeLocalDate(honorData.HonorLastUpdate)
+
+		if honorData.HonorName == "" {
+			return honorData, layoutError("名称")
+		}
+	}
+
+	return honorData, nil
+}
+
+// 荣耀应用市场，网页版没有搜索
+type honorStore struct {
+	unsearchable
+}
+
+func (honorStore) ID() string {
+	return StoreHonor
+}
+
+func (honorStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	honorData, err := c.ParseHonorData(ctx, id)
+	if err != nil {
+		return honorData, err
+	}
+
+	if !honorData.HonorExist {
+		return honorData, ErrNotFound
+	}
+
+	return honorData, nil
+}
+
+func (honorStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	json, err := c.getHonorAppData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getHonorExist(json), nil
+}
+
+func (honorStore) UsesPackageName() bool {
+	return true
+}
+
+// 荣耀应用市场 h5 详情接口
+func (c *Client) getHonorAppData(ctx context.Context, pkgId string) (*gjson.Result, error) {
+	params := url.Values{}
+	params.Add("pkgName", pkgId)
+
+	u := "https://appmarket-h5.hihonor.com/api/h5/app/detail?" + params.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	json := gjson.ParseBytes(bytes)
+	return &json, nil
+}
+
+// 判断是否在荣耀应用市场上架
+func getHonorExist(json *gjson.Result) bool {
+	return json.Get("data.appName").Exists()
+}
+
+// 获取名称
+func getHonorName(json *gjson.Result) string {
+	name := json.Get("data.appName")
+	return strings.TrimSpace(name.String())
+}
+
+// 获取开发者
+func getHonorSupplier(json *gjson.Result) string {
+	supplier := json.Get("data.developerName")
+	return strings.TrimSpace(supplier.String())
+}
+
+// 获取评分
+func getHonorRate(json *gjson.Result) string {
+	rate := json.Get("data.score")
+	return strings.TrimSpace(rate.String())
+}
+
+// 获取版本信息
+func getHonorLastVersion(json *gjson.Result) string {
+	version := json.Get("data.versionName")
+	return strings.TrimSpace(version.String())
+}
+
+// 获取版本更新时间
+func getHonorLastUpdate(json *gjson.Result) string {
+	update := json.Get("data.updateTime")
+	return strings.TrimSpace(update.String())
+}
+
+// 获取包大小，单位字节
+func getHonorPackageSize(json *gjson.Result) string {
+	size := json.Get("data.apkSize")
+	return strings.TrimSpace(size.String())
+}
+
+// 获取隐私政策网址
+func getHonorPrivacyPolicyUrl(json *gjson.Result) string {
+	url := json.Get("data.privacyUrl")
+	return strings.TrimSpace(url.String())
+}
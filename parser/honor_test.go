@@ -0,0 +1,82 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-25 11:20:16
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-25 11:20:16
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var HONOR_APP_ID = "com.ss.android.ugc.aweme"
+
+func TestGetHonorExist(t *testing.T) {
+	json, err := DefaultClient.getHonorAppData(context.Background(), HONOR_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !getHonorExist(json) {
+		t.Error("exist 取错了")
+	}
+
+	json, err = DefaultClient.getHonorAppData(context.Background(), HONOR_APP_ID+"fake")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if getHonorExist(json) {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetHonorName(t *testing.T) {
+	json, err := DefaultClient.getHonorAppData(context.Background(), HONOR_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getHonorName(json)
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetHonorLastVersion(t *testing.T) {
+	json, err := DefaultClient.getHonorAppData(context.Background(), HONOR_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getHonorLastVersion(json)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetHonorPrivacyPolicyUrl(t *testing.T) {
+	json, err := DefaultClient.getHonorAppData(context.Background(), HONOR_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	url := getHonorPrivacyPolicyUrl(json)
+
+	reg := regexp.MustCompile("^http")
+	if !reg.MatchString(url) {
+		t.Error("url 取错了")
+	}
+}
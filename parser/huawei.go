@@ -8,6 +8,7 @@
 package parser
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -23,27 +24,62 @@ const UA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (
 
 // 华为市场
 type HWData struct {
-	HWID               string `bson:"hw_id"`                 // hw id
-	HWPackageID        string `bson:"hw_package_id"`         // hw package id
-	HWName             string `bson:"hw_name"`               // hw 名称
-	HWSupplier         string `bson:"hw_supplier"`           // hw 供应商名称
-	HWRate             string `bson:"hw_rate"`               // hw 评分
-	HWRateCount        string `bson:"hw_rate_count"`         // hw 评价数
-	HWLastVersion      string `bson:"hw_last_version"`       // hw 最新版本
-	HWLastUpdate       string `bson:"hw_last_update"`        // hw 最新版本时间
-	HWPackageSize      string `bson:"hw_package_size"`       // hw 包大小
-	HWPrivacyPolicyUrl string `bson:"hw_privacy_policy_url"` // hw 隐私政策地址
-	HWTargetSDK        string `bson:"hw_target_sdk"`         // hw 不知道是啥，感觉像第三方sdk的数量
-	HWOtherApps        []*App `bson:"hw_other_apps"`         // hw 全部同主体的app
+	HWID               string       `bson:"hw_id"`                 // hw id
+	HWPackageID        string       `bson:"hw_package_id"`         // hw package id
+	HWName             string       `bson:"hw_name"`               // hw 名称
+	HWSupplier         string       `bson:"hw_supplier"`           // hw 供应商名称
+	HWRate             string       `bson:"hw_rate"`               // hw 评分
+	HWRateCount        string       `bson:"hw_rate_count"`         // hw 评价数
+	HWLastVersion      string       `bson:"hw_last_version"`       // hw 最新版本
+	HWLastUpdate       string       `bson:"hw_last_update"`        // hw 最新版本时间
+	HWPackageSize      string       `bson:"hw_package_size"`       // hw 包大小
+	HWPrivacyPolicyUrl string       `bson:"hw_privacy_policy_url"` // hw 隐私政策地址
+	HWTargetSDK        string       `bson:"hw_target_sdk"`         // hw 不知道是啥，感觉像第三方sdk的数量
+	HWOtherApps        []*App       `bson:"hw_other_apps"`         // hw 全部同主体的app
+	HWMedia            Media        `bson:"hw_media"`              // hw 截图与预览视频
+	HWPermissions      []Permission `bson:"hw_permissions"`        // hw 权限列表
+
+	HWRateValue       float64         `bson:"hw_rate_value"`       // hw 评分，数字
+	HWRateCountValue  int64           `bson:"hw_rate_count_value"` // hw 评价数，数字
+	HWPackageBytes    int64           `bson:"hw_package_bytes"`    // hw 包大小，字节数
+	HWLastUpdateTime  time.Time       `bson:"hw_last_update_time"` // hw 最新版本时间
+	HWRatingHistogram RatingHistogram `bson:"hw_rating_histogram"` // hw 各星级的评分数
+}
+
+// 包名
+func (d *HWData) PackageName() string {
+	return d.HWPackageID
 }
 
 // 根据应用名获取华为id
 func GetHWIdByName(name string) string {
-	return getHWAppId(name)
+	return GetHWIdByNameContext(context.Background(), name)
+}
+
+// 根据应用名获取华为id，ctx 取消或超时时中断请求
+func GetHWIdByNameContext(ctx context.Context, name string) string {
+	return DefaultClient.GetHWIdByName(ctx, name)
+}
+
+// 根据应用名获取华为id
+// 出错时也只返回空字符串，需要区分错误时用 GetStore(StoreHW).Search
+func (c *Client) GetHWIdByName(ctx context.Context, name string) string {
+	id, _ := c.getHWAppId(ctx, name)
+	return id
 }
 
 // 获取华为市场数据
 func ParseHWData(hwId string) (*HWData, error) {
+	return ParseHWDataContext(context.Background(), hwId)
+}
+
+// 获取华为市场数据，ctx 取消或超时时中断请求
+func ParseHWDataContext(ctx context.Context, hwId string) (*HWData, error) {
+	return DefaultClient.ParseHWData(ctx, hwId)
+}
+
+// 获取华为市场数据
+func (c *Client) ParseHWData(ctx context.Context, hwId string) (*HWData, error) {
 	// 创建 hw data 结构体
 	hwData := new(HWData)
 
@@ -51,54 +87,97 @@ func ParseHWData(hwId string) (*HWData, error) {
 		return hwData, errors.New("hwId 不能为空")
 	}
 
-	json, err := getHWAppData(hwId)
+	json, err := c.getHWAppData(ctx, hwId)
 	if err != nil {
 		return hwData, err
 	}
 
+	// 不存在的 id 返回的 layoutData 为空
+	if !json.Get("layoutData.0").Exists() {
+		return hwData, ErrNotFound
+	}
+
 	hwData.HWID = hwId
 	hwData.HWPackageID = getHWPackageID(json)
 	hwData.HWName = getHWName(json)
 	hwData.HWSupplier = getHWSupplier(json)
 	hwData.HWRate = getHWRate(json)
 	hwData.HWRateCount = getHWRateCount(json)
+	hwData.HWRatingHistogram = getHWDetailRatingHistogram(json)
 	hwData.HWLastVersion = getHWLastVersion(json)
 	hwData.HWLastUpdate = getHWLastUpdate(json)
 	hwData.HWPackageSize = getHWPackageSize(json)
 	hwData.HWTargetSDK = getHWTargetSDK(json)
 	hwData.HWPrivacyPolicyUrl = getHWPrivacyPolicyUrl(json)
-	hwData.HWOtherApps = getHWOtherApps(json, hwData.HWID)
+	hwData.HWMedia = getHWMedia(json)
+	hwData.HWPermissions = getHWPermissions(json)
+	hwData.HWOtherApps = c.getHWOtherApps(ctx, json, hwData.HWID)
+
+	// 数字与时间
+	hwData.HWRateValue = normalizeRate(hwData.HWRate)
+	hwData.HWRateCountValue = normalizeCount(hwData.HWRateCount)
+	hwData.HWPackageBytes = normalizeSize(hwData.HWPackageSize)
+	hwData.HWLastUpdateTime = normalizeLocalDate(hwData.HWLastUpdate)
+
+	if hwData.HWName == "" {
+		return hwData, layoutError("名称")
+	}
 
 	return hwData, nil
 }
 
+// 华为市场
+type hwStore struct{}
+
+func (hwStore) ID() string {
+	return StoreHW
+}
+
+func (hwStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	return c.ParseHWData(ctx, id)
+}
+
+func (hwStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	return c.getHWAppId(ctx, name)
+}
+
+func (hwStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	json, err := c.getHWAppData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getHWName(json) != "", nil
+}
+
 // 获取华为市场的interface id
-func getHWInterfaceCode() string {
+func (c *Client) getHWInterfaceCode(ctx context.Context) (string, error) {
 	url := "https://web-drcn.hispace.dbankcloud.cn/webedge/getInterfaceCode"
-	client := &http.Client{}
-	request, err := http.NewRequest("POST", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
-		return ""
+		return "", err
 	}
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
-		return ""
+		return "", err
 	}
 
 	defer resp.Body.Close()
 
+	if err := checkStatus(resp); err != nil {
+		return "", err
+	}
+
 	bytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return ""
+		return "", err
 	}
 
-	return strings.ReplaceAll(string(bytes), "\"", "")
+	return strings.ReplaceAll(string(bytes), "\"", ""), nil
 }
 
-func getHWAppId(name string) string {
-	id := getHWInterfaceCode()
-	u := "https://web-drcn.hispace.dbankcloud.cn/uowap/index"
-
+// 根据应用名搜索华为id，没搜到时返回空字符串
+func (c *Client) getHWAppId(ctx context.Context, name string) (string, error) {
 	params := url.Values{}
 	params.Add("method", "internal.getTabDetail")
 	params.Add("serviceType", "20")
@@ -108,45 +187,24 @@ func getHWAppId(name string) string {
 	params.Add("version", "10.0.0")
 	params.Add("locale", "zh")
 
-	client := &http.Client{}
-	request, err := http.NewRequest("GET", u+"?"+params.Encode(), nil)
+	json, err := c.getHWIndexData(ctx, params)
 	if err != nil {
-		return ""
+		return "", err
 	}
 
-	now := time.Now()
-	request.Header.Set("Interface-Code", id+"_"+strconv.Itoa(int(now.UnixMicro())))
-	request.Header.Set("User-Agent", UA)
-
-	resp, err := client.Do(request)
-	if err != nil {
-		return ""
-	}
-
-	defer resp.Body.Close()
-
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ""
-	}
-
-	json := gjson.ParseBytes(bytes)
 	data := json.Get("layoutData.0.dataList.0")
 	findName := data.Get("name").String()
 
 	// 判断名称是否匹配
 	nameContains := strings.Contains(name, findName)
 	if !nameContains {
-		return ""
+		return "", nil
 	}
 
-	return data.Get("appid").String()
+	return data.Get("appid").String(), nil
 }
 
-func getHWAppData(appid string) (*gjson.Result, error) {
-	id := getHWInterfaceCode()
-	u := "https://web-drcn.hispace.dbankcloud.cn/uowap/index"
-
+func (c *Client) getHWAppData(ctx context.Context, appid string) (*gjson.Result, error) {
 	params := url.Values{}
 	params.Add("method", "internal.getTabDetail")
 	params.Add("serviceType", "20")
@@ -155,36 +213,10 @@ func getHWAppData(appid string) (*gjson.Result, error) {
 	params.Add("appid", appid)
 	params.Add("locale", "zh")
 
-	client := &http.Client{}
-	request, err := http.NewRequest("GET", u+"?"+params.Encode(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	now := time.Now()
-	request.Header.Set("Interface-Code", id+"_"+strconv.Itoa(int(now.UnixMicro())))
-	request.Header.Set("User-Agent", UA)
-
-	resp, err := client.Do(request)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	json := gjson.ParseBytes(bytes)
-	return &json, nil
+	return c.getHWIndexData(ctx, params)
 }
 
-func getHWOtherAppsData(appid string) (*gjson.Result, error) {
-	id := getHWInterfaceCode()
-	u := "https://web-drcn.hispace.dbankcloud.cn/uowap/index"
-
+func (c *Client) getHWOtherAppsData(ctx context.Context, appid string) (*gjson.Result, error) {
 	params := url.Values{}
 	params.Add("method", "internal.getTabDetail")
 	params.Add("serviceType", "20")
@@ -193,8 +225,18 @@ func getHWOtherAppsData(appid string) (*gjson.Result, error) {
 	params.Add("maxResults", "25")
 	params.Add("locale", "zh")
 
-	client := &http.Client{}
-	request, err := http.NewRequest("GET", u+"?"+params.Encode(), nil)
+	return c.getHWIndexData(ctx, params)
+}
+
+// 请求华为市场的 uowap/index 接口，每次请求都要带上新的 Interface-Code
+func (c *Client) getHWIndexData(ctx context.Context, params url.Values) (*gjson.Result, error) {
+	id, err := c.getHWInterfaceCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := "https://web-drcn.hispace.dbankcloud.cn/uowap/index"
+	request, err := http.NewRequestWithContext(ctx, "GET", u+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -203,13 +245,17 @@ func getHWOtherAppsData(appid string) (*gjson.Result, error) {
 	request.Header.Set("Interface-Code", id+"_"+strconv.Itoa(int(now.UnixMicro())))
 	request.Header.Set("User-Agent", UA)
 
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
 	bytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -248,6 +294,90 @@ func getHWRateCount(json *gjson.Result) string {
 	return strings.TrimSpace(rate.String())
 }
 
+// 获取详情中的各星级评分数，评分卡片在 layoutData 中的位置不固定
+func getHWDetailRatingHistogram(json *gjson.Result) RatingHistogram {
+	var h RatingHistogram
+
+	json.Get("layoutData").ForEach(func(_, value gjson.Result) bool {
+		list := value.Get("dataList.0.ratingDstList")
+		if list.IsArray() {
+			h = getHWRatingHistogram(list)
+			return false
+		}
+		return true
+	})
+
+	return h
+}
+
+// 获取各星级的评分数，例：[{"rating": 5, "ratingCounts": 1200}]
+func getHWRatingHistogram(list gjson.Result) RatingHistogram {
+	var h RatingHistogram
+
+	list.ForEach(func(_, value gjson.Result) bool {
+		h.set(int(value.Get("rating").Int()), value.Get("ratingCounts").Int())
+		return true
+	})
+
+	return h
+}
+
+// 截图卡片的 layoutName 前缀，例：detailscreencardv3，推荐、相关应用等卡片也有 images，不能混进来
+const hwScreenCardPrefix = "detailscreencard"
+
+// 获取截图与预览视频，只取截图卡片，截图卡片在 layoutData 中的位置不固定
+func getHWMedia(json *gjson.Result) Media {
+	media := Media{
+		Screenshots: make([]*Screenshot, 0),
+		Videos:      make([]*Video, 0),
+	}
+
+	json.Get("layoutData").ForEach(func(_, value gjson.Result) bool {
+		if !strings.HasPrefix(value.Get("layoutName").String(), hwScreenCardPrefix) {
+			return true
+		}
+
+		data := value.Get("dataList.0")
+
+		data.Get("images").ForEach(func(_, image gjson.Result) bool {
+			media.Screenshots = append(media.Screenshots, &Screenshot{URL: image.String()})
+			return true
+		})
+
+		if u := data.Get("videoUrl").String(); u != "" {
+			media.Videos = append(media.Videos, &Video{URL: u, Poster: data.Get("videoPosterUrl").String()})
+		}
+
+		return false
+	})
+
+	return media
+}
+
+// 获取权限列表，权限卡片在 layoutData 中的位置不固定，例：[{"name": "相机", "desc": "拍摄照片和视频"}]
+func getHWPermissions(json *gjson.Result) []Permission {
+	list := make([]Permission, 0)
+
+	json.Get("layoutData").ForEach(func(_, value gjson.Result) bool {
+		permissions := value.Get("dataList.0.permissions")
+		if !permissions.IsArray() {
+			return true
+		}
+
+		permissions.ForEach(func(_, p gjson.Result) bool {
+			list = append(list, Permission{
+				Name:        strings.TrimSpace(p.Get("name").String()),
+				Description: strings.TrimSpace(p.Get("desc").String()),
+			})
+			return true
+		})
+
+		return false
+	})
+
+	return list
+}
+
 // 获取版本信息
 func getHWLastVersion(json *gjson.Result) string {
 	version := json.Get("layoutData.1.dataList.0.versionName")
@@ -281,7 +411,7 @@ func getHWPrivacyPolicyUrl(json *gjson.Result) string {
 }
 
 // 获取 同开发者的其他应用
-func getHWOtherApps(json *gjson.Result, appid string) []*App {
+func (c *Client) getHWOtherApps(ctx context.Context, json *gjson.Result, appid string) []*App {
 	list := json.Get("layoutData.11.dataList.0.list")
 	if !list.IsArray() {
 		return nil
@@ -301,7 +431,7 @@ func getHWOtherApps(json *gjson.Result, appid string) []*App {
 			return true
 		})
 	} else {
-		json, err := getHWOtherAppsData(appid)
+		json, err := c.getHWOtherAppsData(ctx, appid)
 		if err == nil {
 			list = json.Get("layoutData.0.dataList")
 
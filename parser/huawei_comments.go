@@ -0,0 +1,155 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-01 10:26:43
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-01 10:26:43
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// 华为市场评论接口每页的条数
+const hwCommentPageSize = 25
+
+// 华为市场默认翻的页数
+const defaultHWCommentPages = 10
+
+// 获取华为市场评论的配置
+type HWCommentOptions struct {
+	MaxPages int // 最多翻几页，每页 25 条，默认 10 页
+	Limit    int // 最多取多少条，0 为不限制
+}
+
+// 华为市场的一条评论
+type HWComment struct {
+	ID        string    `bson:"id"`         // 评论 id
+	Author    string    `bson:"author"`     // 昵称
+	Content   string    `bson:"content"`    // 内容
+	Rating    int       `bson:"rating"`     // 评分，1 - 5
+	Device    string    `bson:"device"`     // 机型
+	Version   string    `bson:"version"`    // 评论时的应用版本
+	LikeCount int64     `bson:"like_count"` // 点赞数
+	Time      time.Time `bson:"time"`       // 评论时间
+}
+
+// 华为市场的评论
+type HWComments struct {
+	Total    int64           `bson:"total"`    // 评论总数
+	Stars    RatingHistogram `bson:"stars"`    // 各星级的评分数
+	Comments []*HWComment    `bson:"comments"` // 评论列表
+}
+
+// 最多翻几页
+func (o *HWCommentOptions) maxPages() int {
+	if o == nil || o.MaxPages <= 0 {
+		return defaultHWCommentPages
+	}
+	return o.MaxPages
+}
+
+// 最多取多少条，0 为不限制
+func (o *HWCommentOptions) limit() int {
+	if o == nil || o.Limit < 0 {
+		return 0
+	}
+	return o.Limit
+}
+
+// 获取华为市场的评论，hwId 为华为市场的 app id
+func FetchHWComments(hwId string, opts *HWCommentOptions) (*HWComments, error) {
+	return FetchHWCommentsContext(context.Background(), hwId, opts)
+}
+
+// 获取华为市场的评论，ctx 取消或超时时中断请求
+func FetchHWCommentsContext(ctx context.Context, hwId string, opts *HWCommentOptions) (*HWComments, error) {
+	return DefaultClient.FetchHWComments(ctx, hwId, opts)
+}
+
+// 获取华为市场的评论，星级分布取第一页的数据，中途失败时返回已经取到的评论与错误
+func (c *Client) FetchHWComments(ctx context.Context, hwId string, opts *HWCommentOptions) (*HWComments, error) {
+	comments := &HWComments{
+		Comments: make([]*HWComment, 0),
+	}
+
+	if strings.TrimSpace(hwId) == "" {
+		return comments, errors.New("hwId 不能为空")
+	}
+
+	for page := 1; page <= opts.maxPages(); page++ {
+		// 已经取够了，不再请求下一页
+		if opts.limit() > 0 && len(comments.Comments) >= opts.limit() {
+			break
+		}
+
+		json, err := c.getHWCommentsData(ctx, hwId, page)
+		if err != nil {
+			return comments, err
+		}
+
+		if page == 1 {
+			comments.Total = json.Get("count").Int()
+			comments.Stars = getHWRatingHistogram(json.Get("ratingDstList"))
+		}
+
+		list := getHWComments(json)
+		for _, comment := range list {
+			if opts.limit() > 0 && len(comments.Comments) >= opts.limit() {
+				return comments, nil
+			}
+			comments.Comments = append(comments.Comments, comment)
+		}
+
+		// 最后一页，没有 totalPages 时只按条数判断
+		totalPages := json.Get("totalPages").Int()
+		if len(list) < hwCommentPageSize || (totalPages > 0 && int64(page) >= totalPages) {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// 评论接口的一页数据
+func (c *Client) getHWCommentsData(ctx context.Context, appid string, page int) (*gjson.Result, error) {
+	params := url.Values{}
+	params.Add("method", "internal.user.commenList3")
+	params.Add("serviceType", "20")
+	params.Add("reqPageNum", strconv.Itoa(page))
+	params.Add("maxResults", strconv.Itoa(hwCommentPageSize))
+	params.Add("appid", appid)
+	params.Add("version", "10.0.0")
+	params.Add("locale", "zh")
+
+	return c.getHWIndexData(ctx, params)
+}
+
+// 获取一页中的评论
+func getHWComments(json *gjson.Result) []*HWComment {
+	list := make([]*HWComment, 0)
+
+	json.Get("list").ForEach(func(_, value gjson.Result) bool {
+		list = append(list, &HWComment{
+			ID:        strings.TrimSpace(value.Get("commentId").String()),
+			Author:    strings.TrimSpace(value.Get("nickName").String()),
+			Content:   strings.TrimSpace(value.Get("commentInfo").String()),
+			Rating:    int(normalizeRate(value.Get("rating").String())),
+			Device:    strings.TrimSpace(value.Get("phone").String()),
+			Version:   strings.TrimSpace(value.Get("versionName").String()),
+			LikeCount: value.Get("approveCounts").Int(),
+			Time:      normalizeLocalDate(value.Get("operTime").String()),
+		})
+		return true
+	})
+
+	return list
+}
@@ -0,0 +1,108 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-01 11:18:05
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-01 11:18:05
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// 评论接口的一页，n 条评论
+func fakeHWCommentsPage(page, n int) string {
+	list := make([]string, 0)
+	for i := 0; i < n; i++ {
+		list = append(list, fmt.Sprintf(`{
+			"commentId": "%d-%d", "nickName": "花粉", "commentInfo": "好用",
+			"rating": "4.0", "phone": "Mate 40", "versionName": "28.5.0",
+			"approveCounts": "2", "operTime": "2023/07/20 12:34"
+		}`, page, i))
+	}
+	return `{"count": 40, "totalPages": 2, "ratingDstList": [{"rating": 5, "ratingCounts": 30}, {"rating": 1, "ratingCounts": 10}],
+		"list": [` + strings.Join(list, ",") + `]}`
+}
+
+func TestFetchHWComments(t *testing.T) {
+	pages := make([]string, 0)
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if strings.HasSuffix(req.URL.Path, "getInterfaceCode") {
+			return 200, `"code"`
+		}
+
+		if req.Header.Get("Interface-Code") == "" {
+			t.Error("没有带 Interface-Code")
+		}
+
+		page := req.URL.Query().Get("reqPageNum")
+		pages = append(pages, page)
+
+		if page == "1" {
+			return 200, fakeHWCommentsPage(1, 25)
+		}
+		return 200, fakeHWCommentsPage(2, 15)
+	})})
+
+	comments, err := c.FetchHWComments(context.Background(), "C100", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pages) != 2 || len(comments.Comments) != 40 {
+		t.Error("翻页错了", pages, len(comments.Comments))
+	}
+
+	if comments.Total != 40 || comments.Stars.Star5 != 30 || comments.Stars.Star1 != 10 {
+		t.Error("总数或星级分布取错了", comments.Total, comments.Stars)
+	}
+
+	comment := comments.Comments[0]
+	if comment.Rating != 4 || comment.Device != "Mate 40" || comment.Version != "28.5.0" || comment.Time.IsZero() {
+		t.Error("评论取错了", comment)
+	}
+
+	comments, _ = c.FetchHWComments(context.Background(), "C100", &HWCommentOptions{Limit: 10})
+	if len(comments.Comments) != 10 {
+		t.Error("limit 没生效", len(comments.Comments))
+	}
+}
+
+func TestFetchHWCommentsWithoutTotalPages(t *testing.T) {
+	pages := 0
+
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if strings.HasSuffix(req.URL.Path, "getInterfaceCode") {
+			return 200, `"code"`
+		}
+
+		pages++
+		n := 25
+		if req.URL.Query().Get("reqPageNum") == "3" {
+			n = 5
+		}
+		return 200, strings.Replace(fakeHWCommentsPage(pages, n), `"totalPages": 2, `, "", 1)
+	})})
+
+	comments, err := c.FetchHWComments(context.Background(), "C100", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pages != 3 || len(comments.Comments) != 55 {
+		t.Error("没有 totalPages 时应该按条数翻页", pages, len(comments.Comments))
+	}
+
+	// limit 正好是一页时不再请求下一页
+	pages = 0
+	comments, _ = c.FetchHWComments(context.Background(), "C100", &HWCommentOptions{Limit: 25})
+	if pages != 1 || len(comments.Comments) != 25 {
+		t.Error("limit 取够后还在请求", pages, len(comments.Comments))
+	}
+}
@@ -8,23 +8,34 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/tidwall/gjson"
 )
 
 var HW_APP_ID = "C10168892"
 
 func TestGetHWInterfaceCode(t *testing.T) {
-	code := getHWInterfaceCode()
+	code, err := DefaultClient.getHWInterfaceCode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
 	if code == "" {
 		t.Error("code 为空")
 	}
 }
 
 func TestGetHWId(t *testing.T) {
-	id := getHWAppId("抖音")
+	id, err := DefaultClient.getHWAppId(context.Background(), "抖音")
+	if err != nil {
+		t.Fatal(err)
+	}
 	if id == "" {
 		t.Error("id 为空")
 	}
@@ -32,8 +43,56 @@ func TestGetHWId(t *testing.T) {
 	fmt.Println(id)
 }
 
+func TestHWSearchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		codeResp   int
+		searchResp int
+		target     error
+	}{
+		{"search 被拦截", 200, 429, ErrBlocked},
+		{"interface code 被拦截", 403, 200, ErrBlocked},
+		{"interface code 失败", 500, 200, nil},
+	}
+
+	for _, tt := range tests {
+		c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+			if strings.HasSuffix(req.URL.Path, "getInterfaceCode") {
+				return tt.codeResp, `"code"`
+			}
+			return tt.searchResp, `{"layoutData": [{"dataList": [{"name": "抖音", "appid": "C100"}]}]}`
+		})})
+
+		id, err := GetStore(StoreHW).Search(context.Background(), c, "抖音")
+		if err == nil || id != "" {
+			t.Errorf("%v: 应该返回错误 %q %v", tt.name, id, err)
+			continue
+		}
+		if tt.target != nil && !errors.Is(err, tt.target) {
+			t.Errorf("%v: 错误类型不对 %v", tt.name, err)
+		}
+		if c.GetHWIdByName(context.Background(), "抖音") != "" {
+			t.Errorf("%v: GetHWIdByName 出错时应该返回空", tt.name)
+		}
+	}
+}
+
+func TestHWSearch(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		if strings.HasSuffix(req.URL.Path, "getInterfaceCode") {
+			return 200, `"code"`
+		}
+		return 200, `{"layoutData": [{"dataList": [{"name": "抖音", "appid": "C100"}]}]}`
+	})})
+
+	id, err := GetStore(StoreHW).Search(context.Background(), c, "抖音")
+	if err != nil || id != "C100" {
+		t.Error("搜索结果不对", id, err)
+	}
+}
+
 func TestGetHWAppData(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -45,7 +104,7 @@ func TestGetHWAppData(t *testing.T) {
 }
 
 func TestGetHWName(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -59,7 +118,7 @@ func TestGetHWName(t *testing.T) {
 }
 
 func TestGetHWPackageID(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -73,7 +132,7 @@ func TestGetHWPackageID(t *testing.T) {
 }
 
 func TestGetHWSupplier(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -87,7 +146,7 @@ func TestGetHWSupplier(t *testing.T) {
 }
 
 func TestGetHWRate(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -105,7 +164,7 @@ func TestGetHWRate(t *testing.T) {
 }
 
 func TestGetHWRateCount(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -124,7 +183,7 @@ func TestGetHWRateCount(t *testing.T) {
 }
 
 func TestGetHWLastVersion(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -143,7 +202,7 @@ func TestGetHWLastVersion(t *testing.T) {
 }
 
 func TestGetHWLastUpdate(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -162,7 +221,7 @@ func TestGetHWLastUpdate(t *testing.T) {
 }
 
 func TestGetHWPackageSize(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -181,7 +240,7 @@ func TestGetHWPackageSize(t *testing.T) {
 }
 
 func TestGetHWTargetSDK(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -200,7 +259,7 @@ func TestGetHWTargetSDK(t *testing.T) {
 }
 
 func TestGetHWPrivacyPolicyUrl(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -219,13 +278,13 @@ func TestGetHWPrivacyPolicyUrl(t *testing.T) {
 }
 
 func TestGetHWOtherApps(t *testing.T) {
-	json, err := getHWAppData(HW_APP_ID)
+	json, err := DefaultClient.getHWAppData(context.Background(), HW_APP_ID)
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	apps := getHWOtherApps(json, HW_APP_ID)
+	apps := DefaultClient.getHWOtherApps(context.Background(), json, HW_APP_ID)
 
 	for _, a := range apps {
 		if a.Name == "" {
@@ -242,3 +301,43 @@ func TestGetHWOtherApps(t *testing.T) {
 		}
 	}
 }
+
+func TestParseHWDataContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseHWDataContext(ctx, HW_APP_ID)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("ctx 取消后请求没有中断")
+	}
+}
+
+func TestGetHWDetailRatingHistogram(t *testing.T) {
+	json := gjson.Parse(`{"layoutData": [
+		{"dataList": [{"name": "抖音"}]},
+		{"dataList": [{"ratingDstList": [{"rating": 5, "ratingCounts": 300}, {"rating": 3, "ratingCounts": 20}, {"rating": 6, "ratingCounts": 1}]}]}
+	]}`)
+
+	h := getHWDetailRatingHistogram(&json)
+	if h.Star5 != 300 || h.Get(3) != 20 || h.Total() != 320 {
+		t.Error("histogram 取错了", h)
+	}
+}
+
+func TestGetHWMedia(t *testing.T) {
+	json := gjson.Parse(`{"layoutData": [
+		{"layoutName": "detailheadcard", "dataList": [{"name": "抖音"}]},
+		{"layoutName": "detailscreencardv3", "dataList": [{"images": ["https://a/1.jpg", "https://a/2.jpg"],
+			"videoUrl": "https://a/preview.mp4", "videoPosterUrl": "https://a/poster.jpg"}]},
+		{"layoutName": "horizonhomecardv2", "dataList": [{"images": ["https://a/ad.jpg"], "videoUrl": "https://a/ad.mp4"}]}
+	]}`)
+
+	media := getHWMedia(&json)
+	if len(media.Screenshots) != 2 || media.Screenshots[1].URL != "https://a/2.jpg" {
+		t.Error("截图取错了，不应该包含推荐卡片", media.Screenshots)
+	}
+
+	if len(media.Videos) != 1 || media.Videos[0].Poster != "https://a/poster.jpg" {
+		t.Error("视频取错了", media.Videos)
+	}
+}
@@ -0,0 +1,368 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-04 10:22:09
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-04 10:22:09
+ * @Description:
+ */
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"math/bits"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// 感知哈希的算法
+type IconHashAlgorithm string
+
+const (
+	IconAHash IconHashAlgorithm = "ahash" // 均值哈希，最快，对亮度变化敏感
+	IconDHash IconHashAlgorithm = "dhash" // 差值哈希，对渐变与缩放较稳定
+	IconPHash IconHashAlgorithm = "phash" // dct 哈希，最稳定，默认
+)
+
+// 默认的汉明距离阈值，64 位哈希中不同的位数不超过它时认为相似
+const defaultIconThreshold = 10
+
+// 图标的感知哈希
+type IconHash struct {
+	AHash uint64 `bson:"ahash"`
+	DHash uint64 `bson:"dhash"`
+	PHash uint64 `bson:"phash"`
+}
+
+// 两个图标的汉明距离
+type IconDistance struct {
+	AHash int `bson:"ahash"`
+	DHash int `bson:"dhash"`
+	PHash int `bson:"phash"`
+}
+
+// 比较图标的配置
+type IconCompareOptions struct {
+	Algorithm IconHashAlgorithm // 判断相似用的算法，默认 IconPHash
+	Threshold *int              // 汉明距离阈值，nil 时默认 10，0 表示只要完全相同的
+}
+
+// 参与比较的应用，Market 为市场 id
+type IconCandidate struct {
+	Market string `bson:"market"`
+	App    *App   `bson:"app"`
+}
+
+// 图标相似的应用
+type IconMatch struct {
+	Market   string       `bson:"market"`   // 市场 id
+	App      *App         `bson:"app"`      // 应用
+	Distance IconDistance `bson:"distance"` // 与参照图标的汉明距离
+}
+
+// 两个哈希的汉明距离
+func (h IconHash) Distance(o IconHash) IconDistance {
+	return IconDistance{
+		AHash: bits.OnesCount64(h.AHash ^ o.AHash),
+		DHash: bits.OnesCount64(h.DHash ^ o.DHash),
+		PHash: bits.OnesCount64(h.PHash ^ o.PHash),
+	}
+}
+
+// 某个算法的距离
+func (d IconDistance) Get(algorithm IconHashAlgorithm) int {
+	switch algorithm {
+	case IconAHash:
+		return d.AHash
+	case IconDHash:
+		return d.DHash
+	}
+	return d.PHash
+}
+
+// 算法，默认 phash
+func (o *IconCompareOptions) algorithm() IconHashAlgorithm {
+	if o == nil || o.Algorithm == "" {
+		return IconPHash
+	}
+	return o.Algorithm
+}
+
+// 阈值，默认 10
+func (o *IconCompareOptions) threshold() int {
+	if o == nil || o.Threshold == nil || *o.Threshold < 0 {
+		return defaultIconThreshold
+	}
+	return *o.Threshold
+}
+
+// 市场数据中同开发者应用的图标，用于和参照图标比较
+func (d *APPData) IconCandidates() []*IconCandidate {
+	list := make([]*IconCandidate, 0)
+
+	add := func(market string, apps []*App) {
+		for _, app := range apps {
+			if app != nil && app.Icon != "" {
+				list = append(list, &IconCandidate{Market: market, App: app})
+			}
+		}
+	}
+
+	add(StoreIOS, d.IOS().IOSOtherApps)
+	add(StoreHW, d.HW().HWOtherApps)
+	add(StoreGP, d.GP().GPOtherApps)
+
+	return list
+}
+
+// 计算图片的感知哈希
+func HashIcon(img image.Image) IconHash {
+	return IconHash{
+		AHash: aHash(img),
+		DHash: dHash(img),
+		PHash: pHash(img),
+	}
+}
+
+// 计算图片内容的感知哈希，支持 png、jpeg、gif
+// 标准库没有 webp 的解码器，webp 与不认识的格式返回 ErrUnsupportedImage，调用方可以自行 import golang.org/x/image/webp 注册解码器
+func HashIconData(data []byte) (IconHash, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		if _, _, _, ok := decodeWebPConfig(data); ok {
+			return IconHash{}, fmt.Errorf("%w: webp", ErrUnsupportedImage)
+		}
+		return IconHash{}, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if err != nil {
+		return IconHash{}, err
+	}
+
+	return HashIcon(img), nil
+}
+
+// 下载图标并计算感知哈希
+func FetchIconHash(u string) (IconHash, error) {
+	return FetchIconHashContext(context.Background(), u)
+}
+
+// 下载图标并计算感知哈希，ctx 取消或超时时中断请求
+func FetchIconHashContext(ctx context.Context, u string) (IconHash, error) {
+	return DefaultClient.FetchIconHash(ctx, u)
+}
+
+// 下载图标并计算感知哈希
+func (c *Client) FetchIconHash(ctx context.Context, u string) (IconHash, error) {
+	if u == "" {
+		return IconHash{}, errors.New("图标地址不能为空")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return IconHash{}, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return IconHash{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return IconHash{}, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return IconHash{}, err
+	}
+
+	return HashIconData(data)
+}
+
+// 找出图标与参照图标 refIcon 相似的应用
+func FindIconClones(refIcon string, candidates []*IconCandidate, opts *IconCompareOptions) ([]*IconMatch, error) {
+	return FindIconClonesContext(context.Background(), refIcon, candidates, opts)
+}
+
+// 找出图标与参照图标相似的应用，ctx 取消或超时时中断请求
+func FindIconClonesContext(ctx context.Context, refIcon string, candidates []*IconCandidate, opts *IconCompareOptions) ([]*IconMatch, error) {
+	return DefaultClient.FindIconClones(ctx, refIcon, candidates, opts)
+}
+
+// 找出图标与参照图标相似的应用，按距离从近到远排序，没有图标、下载或解码失败（包括 webp 等不支持的格式）的应用跳过
+// 参照图标失败时返回错误，可以用 errors.Is 判断 ErrUnsupportedImage
+func (c *Client) FindIconClones(ctx context.Context, refIcon string, candidates []*IconCandidate, opts *IconCompareOptions) ([]*IconMatch, error) {
+	ref, err := c.FetchIconHash(ctx, refIcon)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	matches := make([]*IconMatch, 0)
+	tasks := make([]func(), 0)
+
+	for _, candidate := range candidates {
+		candidate := candidate
+		if candidate == nil || candidate.App == nil || candidate.App.Icon == "" {
+			continue
+		}
+
+		tasks = append(tasks, func() {
+			hash, err := c.FetchIconHash(ctx, candidate.App.Icon)
+			if err != nil {
+				return
+			}
+
+			distance := ref.Distance(hash)
+			if distance.Get(opts.algorithm()) > opts.threshold() {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			matches = append(matches, &IconMatch{
+				Market:   candidate.Market,
+				App:      candidate.App,
+				Distance: distance,
+			})
+		})
+	}
+
+	c.parallel(tasks...)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance.Get(opts.algorithm()) < matches[j].Distance.Get(opts.algorithm())
+	})
+
+	return matches, ctx.Err()
+}
+
+// 缩放成 w x h 的灰度图，每个格子取区域内的平均亮度
+func grayscale(img image.Image, w, h int) [][]float64 {
+	b := img.Bounds()
+	pixels := make([][]float64, h)
+
+	for y := 0; y < h; y++ {
+		pixels[y] = make([]float64, w)
+
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := b.Min.Y + (y+1)*b.Dy()/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := b.Min.X + (x+1)*b.Dx()/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			sum, n := 0.0, 0
+			for yy := y0; yy < y1; yy++ {
+				for xx := x0; xx < x1; xx++ {
+					r, g, bb, _ := img.At(xx, yy).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bb)
+					n++
+				}
+			}
+
+			pixels[y][x] = sum / float64(n)
+		}
+	}
+
+	return pixels
+}
+
+// 均值哈希，8x8 的灰度图中亮于平均值的位为 1
+func aHash(img image.Image) uint64 {
+	pixels := grayscale(img, 8, 8)
+
+	mean := 0.0
+	for _, row := range pixels {
+		for _, v := range row {
+			mean += v
+		}
+	}
+	mean /= 64
+
+	var hash uint64
+	for _, row := range pixels {
+		for _, v := range row {
+			hash <<= 1
+			if v > mean {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// 差值哈希，9x8 的灰度图中左边比右边亮的位为 1
+func dHash(img image.Image) uint64 {
+	pixels := grayscale(img, 9, 8)
+
+	var hash uint64
+	for _, row := range pixels {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if row[x] > row[x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// dct 哈希，32x32 的灰度图做 dct，取左上角 8x8 的低频部分（去掉直流分量）与中位数比较
+func pHash(img image.Image) uint64 {
+	const size = 32
+
+	pixels := grayscale(img, size, size)
+
+	// 二维 dct，只计算需要的左上角 8x8
+	coeffs := make([]float64, 0, 64)
+	for u := 0; u < 8; u++ {
+		for v := 0; v < 8; v++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					sum += pixels[y][x] *
+						math.Cos(float64(2*y+1)*float64(u)*math.Pi/(2*size)) *
+						math.Cos(float64(2*x+1)*float64(v)*math.Pi/(2*size))
+				}
+			}
+			coeffs = append(coeffs, sum)
+		}
+	}
+
+	sorted := append([]float64(nil), coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, v := range coeffs {
+		hash <<= 1
+		if i > 0 && v > median {
+			hash |= 1
+		}
+	}
+
+	return hash
+}
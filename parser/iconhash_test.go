@@ -0,0 +1,151 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-04 11:52:36
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-04 11:52:36
+ * @Description:
+ */
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// 生成一张 size x size 的图，pattern 决定每个像素的亮度
+func fakeIcon(size int, pattern func(x, y int) uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetGray(x, y, color.Gray{Y: pattern(x*64/size, y*64/size)})
+		}
+	}
+	return img
+}
+
+// 对角渐变
+func gradientPattern(x, y int) uint8 {
+	return uint8((x + y) * 2)
+}
+
+// 棋盘格
+func checkerPattern(x, y int) uint8 {
+	if (x/8+y/8)%2 == 0 {
+		return 255
+	}
+	return 0
+}
+
+func fakeIconPNG(img image.Image) string {
+	buf := new(bytes.Buffer)
+	png.Encode(buf, img)
+	return buf.String()
+}
+
+func TestHashIcon(t *testing.T) {
+	a := HashIcon(fakeIcon(64, gradientPattern))
+
+	// 同一张图缩放后应该很接近
+	b := HashIcon(fakeIcon(256, gradientPattern))
+	if d := a.Distance(b); d.AHash > defaultIconThreshold || d.DHash > defaultIconThreshold || d.PHash > defaultIconThreshold {
+		t.Error("缩放后的距离太大", d)
+	}
+
+	// 不同的图应该相差很远
+	c := HashIcon(fakeIcon(64, checkerPattern))
+	if d := a.Distance(c); d.PHash <= defaultIconThreshold {
+		t.Error("不同图片的距离太小", d)
+	}
+}
+
+func TestFindIconClones(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		switch req.URL.Path {
+		case "/ref.png":
+			return 200, fakeIconPNG(fakeIcon(64, gradientPattern))
+		case "/same.png":
+			return 200, fakeIconPNG(fakeIcon(64, gradientPattern))
+		case "/clone.png":
+			return 200, fakeIconPNG(fakeIcon(128, gradientPattern))
+		case "/other.png":
+			return 200, fakeIconPNG(fakeIcon(64, checkerPattern))
+		}
+		return 404, ""
+	})})
+
+	candidates := []*IconCandidate{
+		{Market: StoreHW, App: &App{ID: "1", Icon: "https://x/clone.png"}},
+		{Market: StoreIOS, App: &App{ID: "2", Icon: "https://x/other.png"}},
+		{Market: StoreGP, App: &App{ID: "3", Icon: "https://x/missing.png"}},
+		{Market: StoreMI},
+		nil,
+	}
+
+	matches, err := c.FindIconClones(context.Background(), "https://x/ref.png", candidates, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 || matches[0].App.ID != "1" || matches[0].Market != StoreHW {
+		t.Error("相似的应用找错了", matches)
+	}
+
+	// 阈值为 0 时只要完全相同的
+	exact := 0
+	candidates = append(candidates, &IconCandidate{Market: StoreQQ, App: &App{ID: "4", Icon: "https://x/same.png"}})
+
+	matches, err = c.FindIconClones(context.Background(), "https://x/ref.png", candidates, &IconCompareOptions{Threshold: &exact})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, m := range matches {
+		if m.Distance.PHash != 0 {
+			t.Error("阈值为 0 时不应该有距离大于 0 的", m.Distance)
+		}
+		found = found || m.App.ID == "4"
+	}
+	if !found {
+		t.Error("完全相同的图标没找到", matches)
+	}
+
+	if _, err := c.FindIconClones(context.Background(), "https://x/missing.png", candidates, nil); err == nil {
+		t.Error("参照图标下载失败时应该返回错误")
+	}
+}
+
+func TestAPPDataIconCandidates(t *testing.T) {
+	data := &APPData{Markets: map[string]interface{}{
+		StoreIOS: &IOSData{IOSOtherApps: []*App{{ID: "1", Icon: "a"}, {ID: "2"}}},
+		StoreHW:  &HWData{HWOtherApps: []*App{{ID: "3", Icon: "b"}}},
+	}}
+
+	if list := data.IconCandidates(); len(list) != 2 || list[1].Market != StoreHW {
+		t.Error("candidates 取错了", list)
+	}
+}
+
+func TestHashIconDataFormats(t *testing.T) {
+	if _, err := HashIconData([]byte(fakeIconPNG(fakeIcon(64, gradientPattern)))); err != nil {
+		t.Error("png 应该可以解码", err)
+	}
+
+	// VP8X 的文件头，标准库解不了
+	webp := make([]byte, 30)
+	copy(webp, "RIFF\x00\x00\x00\x00WEBPVP8X")
+	if _, err := HashIconData(webp); !errors.Is(err, ErrUnsupportedImage) || !strings.Contains(err.Error(), "webp") {
+		t.Error("webp 应该返回 ErrUnsupportedImage", err)
+	}
+
+	if _, err := HashIconData([]byte("not an image")); !errors.Is(err, ErrUnsupportedImage) {
+		t.Error("不认识的格式应该返回 ErrUnsupportedImage", err)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-02 10:08:31
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-02 10:08:31
+ * @Description:
+ */
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 截图
+type Screenshot struct {
+	Device string `bson:"device"` // 设备，ios 为 iphone、ipad、mac 等，安卓市场为空
+	URL    string `bson:"url"`    // 图片地址
+	Width  int    `bson:"width"`  // 图片宽度，取不到时为 0
+}
+
+// 预览视频
+type Video struct {
+	Device string `bson:"device"` // 设备，同 Screenshot.Device
+	URL    string `bson:"url"`    // 视频地址，app store 为 m3u8
+	Poster string `bson:"poster"` // 封面图地址
+}
+
+// 应用的截图与预览视频
+type Media struct {
+	Screenshots []*Screenshot `bson:"screenshots"` // 截图
+	Videos      []*Video      `bson:"videos"`      // 预览视频
+}
+
+// 某个设备的截图，device 为空时返回全部
+func (m *Media) ScreenshotsFor(device string) []*Screenshot {
+	if device == "" {
+		return m.Screenshots
+	}
+
+	list := make([]*Screenshot, 0)
+	for _, v := range m.Screenshots {
+		if v.Device == device {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// 是否没有任何截图与视频
+func (m *Media) IsEmpty() bool {
+	return len(m.Screenshots) == 0 && len(m.Videos) == 0
+}
+
+// 从 srcset 中选一张图，width 为想要的宽度，取不小于 width 的最小尺寸，都比 width 小或 width 为 0 时取最大的
+// 例：https://a.png 300w, https://b.png 600w
+func pickSrcset(srcset string, width int) (string, int) {
+	var (
+		best, largest           string
+		bestWidth, largestWidth int
+	)
+
+	for _, v := range strings.Split(srcset, ",") {
+		fields := strings.Fields(strings.TrimSpace(v))
+		if len(fields) == 0 {
+			continue
+		}
+
+		w := 0
+		if len(fields) > 1 {
+			w, _ = strconv.Atoi(strings.TrimSuffix(fields[1], "w"))
+		}
+
+		if largest == "" || w > largestWidth {
+			largest, largestWidth = fields[0], w
+		}
+
+		if width > 0 && w >= width && (best == "" || w < bestWidth) {
+			best, bestWidth = fields[0], w
+		}
+	}
+
+	if best != "" {
+		return best, bestWidth
+	}
+
+	return largest, largestWidth
+}
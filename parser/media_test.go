@@ -0,0 +1,46 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-02 14:32:10
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-02 14:32:10
+ * @Description:
+ */
+package parser
+
+import "testing"
+
+func TestPickSrcset(t *testing.T) {
+	srcset := "https://a/300x650bb.png 300w, https://a/600x1300bb.png 600w, https://a/157x340bb.png 157w"
+
+	if u, w := pickSrcset(srcset, 0); u != "https://a/600x1300bb.png" || w != 600 {
+		t.Error("默认应该取最大的", u, w)
+	}
+
+	if u, w := pickSrcset(srcset, 200); u != "https://a/300x650bb.png" || w != 300 {
+		t.Error("应该取不小于 200 的最小尺寸", u, w)
+	}
+
+	if u, _ := pickSrcset(srcset, 1000); u != "https://a/600x1300bb.png" {
+		t.Error("都比 1000 小时应该取最大的", u)
+	}
+
+	if u, _ := pickSrcset("", 300); u != "" {
+		t.Error("空的 srcset 应该返回空", u)
+	}
+}
+
+func TestMediaScreenshotsFor(t *testing.T) {
+	media := Media{Screenshots: []*Screenshot{
+		{Device: IOSPlatformIPhone, URL: "a"},
+		{Device: IOSPlatformIPad, URL: "b"},
+		{Device: IOSPlatformIPhone, URL: "c"},
+	}}
+
+	if len(media.ScreenshotsFor(IOSPlatformIPhone)) != 2 || len(media.ScreenshotsFor("")) != 3 {
+		t.Error("按设备筛选错了")
+	}
+
+	if media.IsEmpty() || !(&Media{}).IsEmpty() {
+		t.Error("IsEmpty 错了")
+	}
+}
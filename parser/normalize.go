@@ -0,0 +1,143 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-14 15:08:52
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-14 15:08:52
+ * @Description:
+ */
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 数字加单位，例：1.2万、3,456、256.3 MB
+var numberUnitReg = regexp.MustCompile(`([0-9][0-9,]*(?:\.[0-9]+)?)\s*([A-Za-z万亿]*)`)
+
+// 评分，例：4.8、4.5分，取不到时返回 0
+func normalizeRate(s string) float64 {
+	m := numberUnitReg.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+
+	rate, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	return rate
+}
+
+// 数量，支持 万、亿、K、M、B 单位，例：1.2万、3,456次评分、1.5K，取不到时返回 0
+func normalizeCount(s string) int64 {
+	m := numberUnitReg.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+
+	n, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	switch strings.ToUpper(m[2]) {
+	case "万":
+		n *= 1e4
+	case "亿":
+		n *= 1e8
+	case "K":
+		n *= 1e3
+	case "M":
+		n *= 1e6
+	case "B":
+		n *= 1e9
+	}
+
+	return int64(n + 0.5)
+}
+
+// 包大小，转成字节数，没有单位时视为字节，例：256.3 MB、1.2GB、123456，取不到时返回 0
+// app store、google play 与国内市场显示的都是十进制的 MB，统一按 1000 换算，才能和 lookup 接口的 fileSizeBytes 对上
+func normalizeSize(s string) int64 {
+	return normalizeSizeUnit(s, "")
+}
+
+// 包大小，转成字节数，s 中没有单位时使用 unit，KiB、MiB、GiB 按 1024 换算，例：vivo 接口的大小只有数字，单位是 KiB
+func normalizeSizeUnit(s string, unit string) int64 {
+	m := numberUnitReg.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+
+	n, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	if m[2] != "" {
+		unit = m[2]
+	}
+
+	switch strings.ToUpper(unit) {
+	case "K", "KB":
+		n *= 1e3
+	case "M", "MB":
+		n *= 1e6
+	case "G", "GB":
+		n *= 1e9
+	case "KIB":
+		n *= 1 << 10
+	case "MIB":
+		n *= 1 << 20
+	case "GIB":
+		n *= 1 << 30
+	}
+
+	return int64(n + 0.5)
+}
+
+// 各市场出现过的日期格式
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-1-2",
+	"2006/1/2 15:04",
+	"2006/1/2",
+	"2006.1.2",
+	"20060102",
+	"2006年1月2日",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// 国内市场页面上的时间都是北京时间，中国没有夏令时，用固定时区不依赖系统的 tzdata
+var chinaLocation = time.FixedZone("Asia/Shanghai", 8*60*60)
+
+// 日期，没有时区的按 UTC 处理，取不到时返回零值
+func normalizeDate(s string) time.Time {
+	return normalizeDateIn(s, time.UTC)
+}
+
+// 国内市场的日期，没有时区的按北京时间处理，取不到时返回零值
+func normalizeLocalDate(s string) time.Time {
+	return normalizeDateIn(s, chinaLocation)
+}
+
+// 日期，没有时区的按 loc 处理，取不到时返回零值
+func normalizeDateIn(s string, loc *time.Location) time.Time {
+	s = strings.TrimSpace(s)
+
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
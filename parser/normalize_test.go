@@ -0,0 +1,120 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-14 15:40:31
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-14 15:40:31
+ * @Description:
+ */
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeRate(t *testing.T) {
+	cases := map[string]float64{
+		"4.8":  4.8,
+		"4.5分": 4.5,
+		"":     0,
+		"暂无评分": 0,
+	}
+
+	for s, want := range cases {
+		if got := normalizeRate(s); got != want {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+}
+
+func TestNormalizeCount(t *testing.T) {
+	cases := map[string]int64{
+		"1.2万":      12000,
+		"3亿":        300000000,
+		"3,456次评分":  3456,
+		"1.5K":      1500,
+		"2.1M":      2100000,
+		"123456":    123456,
+		"(8901次评分)": 8901,
+		"":          0,
+	}
+
+	for s, want := range cases {
+		if got := normalizeCount(s); got != want {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+}
+
+func TestNormalizeSize(t *testing.T) {
+	cases := map[string]int64{
+		"256.3 MB": 256300000,
+		"1.2GB":    1200000000,
+		"512 KB":   512000,
+		"123456":   123456,
+		"":         0,
+	}
+
+	for s, want := range cases {
+		if got := normalizeSize(s); got != want {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+}
+
+func TestNormalizeSizeUnit(t *testing.T) {
+	cases := map[string]int64{
+		"1024":    1024000,
+		"12MB":    12000000,
+		"1,024.5": 1024500,
+		"":        0,
+	}
+
+	for s, want := range cases {
+		if got := normalizeSizeUnit(s, "KB"); got != want {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+
+	// vivo 的大小是 KiB，按 1024 换算
+	binary := map[string]int64{
+		"262144":  268435456,
+		"1.5 MiB": 1572864,
+		"":        0,
+	}
+
+	for s, want := range binary {
+		if got := normalizeSizeUnit(s, "KiB"); got != want {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+}
+
+func TestNormalizeDate(t *testing.T) {
+	want := time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range []string{"2023-07-03", "2023-7-3", "2023/07/03", "2023.07.03", "20230703", "2023年7月3日", "Jul 3, 2023", "2023-07-03 00:00:00", "2023/07/03 00:00"} {
+		if got := normalizeDate(s); !got.Equal(want) {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+
+	if !normalizeDate("最近").IsZero() {
+		t.Error("取不到时应该返回零值")
+	}
+}
+
+func TestNormalizeLocalDate(t *testing.T) {
+	want := time.Date(2023, 7, 20, 2, 0, 0, 0, time.UTC)
+
+	for _, s := range []string{"2023-07-20 10:00", "2023/7/20 10:00"} {
+		if got := normalizeLocalDate(s); !got.Equal(want) {
+			t.Errorf("%v 应该是 %v，实际是 %v", s, want, got)
+		}
+	}
+
+	// 带时区的不受影响
+	if got := normalizeLocalDate("2023-07-20T02:00:00Z"); !got.Equal(want) {
+		t.Error("带时区的日期取错了", got)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-21 10:15:32
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-21 10:15:32
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// oppo 软件商店
+type OPPOData struct {
+	OPPOExist         bool   `bson:"oppo_exist"`          // oppo 是否有
+	OPPOPackageID     string `bson:"oppo_package_id"`     // oppo package id
+	OPPOName          string `bson:"oppo_name"`           // oppo 名称
+	OPPOSupplier      string `bson:"oppo_supplier"`       // oppo 开发者
+	OPPORate          string `bson:"oppo_rate"`           // oppo 评分
+	OPPODownloadCount string `bson:"oppo_download_count"` // oppo 下载量
+	OPPOLastVersion   string `bson:"oppo_last_version"`   // oppo 最新版本
+	OPPOLastUpdate    string `bson:"oppo_last_update"`    // oppo 最新版本时间
+
+	OPPORateValue          float64   `bson:"oppo_rate_value"`           // oppo 评分，数字
+	OPPODownloadCountValue int64     `bson:"oppo_download_count_value"` // oppo 下载量，数字
+	OPPOLastUpdateTime     time.Time `bson:"oppo_last_update_time"`     // oppo 最新版本时间
+}
+
+// 包名
+func (d *OPPOData) PackageName() string {
+	return d.OPPOPackageID
+}
+
+// 获取 oppo 软件商店数据
+func ParseOPPOData(pkgId string) (*OPPOData, error) {
+	return ParseOPPODataContext(context.Background(), pkgId)
+}
+
+// 获取 oppo 软件商店数据，ctx 取消或超时时中断请求
+func ParseOPPODataContext(ctx context.Context, pkgId string) (*OPPOData, error) {
+	return DefaultClient.ParseOPPOData(ctx, pkgId)
+}
+
+// 获取 oppo 软件商店数据
+func (c *Client) ParseOPPOData(ctx context.Context, pkgId string) (*OPPOData, error) {
+	// 创建 oppo data 结构体
+	oppoData := new(OPPOData)
+
+	if strings.TrimSpace(pkgId) == "" {
+		return oppoData, errors.New("pkgId 不能为空")
+	}
+
+	doc, err := c.getOPPODoc(ctx, pkgId)
+	if err != nil {
+		return oppoData, err
+	}
+
+	oppoData.OPPOExist = getOPPOExist(doc)
+	if oppoData.OPPOExist {
+		oppoData.OPPOPackageID = pkgId
+		oppoData.OPPOName = getOPPOName(doc)
+		oppoData.OPPOSupplier = getOPPOSupplier(doc)
+		oppoData.OPPORate = getOPPORate(doc)
+		oppoData.OPPODownloadCount = getOPPODownloadCount(doc)
+		oppoData.OPPOLastVersion = getOPPOLastVersion(doc)
+		oppoData.OPPOLastUpdate = getOPPOLastUpdate(doc)
+		oppoData.OPPORateValue = normalizeRate(oppoData.OPPORate)
+		oppoData.OPPODownloadCountValue = normalizeCount(oppoData.OPPODownloadCount)
+		oppoData.OPPOLastUpdateTime = normalizeLocalDate(oppoData.OPPOLastUpdate)
+
+		if oppoData.OPPOName == "" {
+			return oppoData, layoutError("名称")
+		}
+	}
+
+	return oppoData, nil
+}
+
+// oppo 软件商店，网页版没有搜索
+type oppoStore struct {
+	unsearchable
+}
+
+func (oppoStore) ID() string {
+	return StoreOPPO
+}
+
+func (oppoStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	oppoData, err := c.ParseOPPOData(ctx, id)
+	if err != nil {
+		return oppoData, err
+	}
+
+	if !oppoData.OPPOExist {
+		return oppoData, ErrNotFound
+	}
+
+	return oppoData, nil
+}
+
+func (oppoStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	doc, err := c.getOPPODoc(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getOPPOExist(doc), nil
+}
+
+func (oppoStore) UsesPackageName() bool {
+	return true
+}
+
+func (c *Client) getOPPODoc(ctx context.Context, id string) (*goquery.Document, error) {
+	u := "https://store.oppomobile.com/product/detail.html?pkg=" + id
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 判断是否在 oppo 软件商店上架
+func getOPPOExist(doc *goquery.Document) bool {
+	node := doc.Find(".detail-info .app-name")
+	return node.Length() > 0
+}
+
+// 获取名称
+func getOPPOName(doc *goquery.Document) string {
+	node := doc.Find(".detail-info .app-name")
+	return strings.TrimSpace(node.Text())
+}
+
+// 获取评分
+func getOPPORate(doc *goquery.Document) string {
+	node := doc.Find(".detail-info .app-score")
+	return strings.TrimSpace(node.Text())
+}
+
+// 获取下载量
+func getOPPODownloadCount(doc *goquery.Document) string {
+	node := doc.Find(".detail-info .app-download")
+	txt := node.Text()
+	txt = strings.ReplaceAll(txt, "次下载", "")
+	txt = strings.ReplaceAll(txt, "下载", "")
+	return strings.TrimSpace(txt)
+}
+
+// 获取开发者
+func getOPPOSupplier(doc *goquery.Document) string {
+	return getOPPOAttr(doc, "开发者")
+}
+
+// 获取最新版本号
+func getOPPOLastVersion(doc *goquery.Document) string {
+	return getOPPOAttr(doc, "版本")
+}
+
+// 获取最新更新时间
+func getOPPOLastUpdate(doc *goquery.Document) string {
+	return getOPPOAttr(doc, "更新时间")
+}
+
+// 获取详细信息中的一项，例：版本：1.0.0
+func getOPPOAttr(doc *goquery.Document, label string) string {
+	node := doc.Find(".detail-attr li")
+
+	value := ""
+
+	node.Map(func(i int, s *goquery.Selection) string {
+		text := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(text, label) {
+			value = strings.TrimPrefix(text, label)
+			value = strings.TrimLeft(value, "：: ")
+		}
+		return ""
+	})
+
+	return strings.TrimSpace(value)
+}
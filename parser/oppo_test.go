@@ -0,0 +1,98 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-21 11:02:47
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-21 11:02:47
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var OPPO_APP_ID = "com.ss.android.ugc.aweme"
+
+func TestGetOPPOExist(t *testing.T) {
+	doc, err := DefaultClient.getOPPODoc(context.Background(), OPPO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	exist := getOPPOExist(doc)
+	if exist == false {
+		t.Error("exist 取错了")
+	}
+
+	doc, err = DefaultClient.getOPPODoc(context.Background(), OPPO_APP_ID+"fake")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	exist = getOPPOExist(doc)
+	if exist == true {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetOPPOName(t *testing.T) {
+	doc, err := DefaultClient.getOPPODoc(context.Background(), OPPO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getOPPOName(doc)
+
+	if name == "" {
+		t.Error("name 取错了")
+	}
+}
+
+func TestGetOPPOLastVersion(t *testing.T) {
+	doc, err := DefaultClient.getOPPODoc(context.Background(), OPPO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getOPPOLastVersion(doc)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetOPPOLastUpdate(t *testing.T) {
+	doc, err := DefaultClient.getOPPODoc(context.Background(), OPPO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	update := getOPPOLastUpdate(doc)
+
+	reg := regexp.MustCompile("^[0-9-]+$")
+	if !reg.MatchString(update) {
+		t.Error("update 取错了")
+	}
+}
+
+func TestGetOPPODownloadCount(t *testing.T) {
+	doc, err := DefaultClient.getOPPODoc(context.Background(), OPPO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	count := getOPPODownloadCount(doc)
+
+	if normalizeCount(count) == 0 {
+		t.Error("downloadCount 取错了")
+	}
+}
@@ -7,6 +7,12 @@
  */
 package parser
 
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
 type App struct {
 	ID       string `bson:"id"`
 	Name     string `bson:"name"`
@@ -14,50 +20,319 @@ type App struct {
 	Icon     string `bson:"icon"`
 }
 
+// 1 - 5 星的评分分布
+type RatingHistogram struct {
+	Star1 int64 `bson:"star_1"` // 1 星的评分数
+	Star2 int64 `bson:"star_2"` // 2 星的评分数
+	Star3 int64 `bson:"star_3"` // 3 星的评分数
+	Star4 int64 `bson:"star_4"` // 4 星的评分数
+	Star5 int64 `bson:"star_5"` // 5 星的评分数
+}
+
+// 某个星级的评分数，star 不在 1 - 5 时返回 0
+func (h *RatingHistogram) Get(star int) int64 {
+	switch star {
+	case 1:
+		return h.Star1
+	case 2:
+		return h.Star2
+	case 3:
+		return h.Star3
+	case 4:
+		return h.Star4
+	case 5:
+		return h.Star5
+	}
+	return 0
+}
+
+// 设置某个星级的评分数，star 不在 1 - 5 时忽略
+func (h *RatingHistogram) set(star int, n int64) {
+	switch star {
+	case 1:
+		h.Star1 = n
+	case 2:
+		h.Star2 = n
+	case 3:
+		h.Star3 = n
+	case 4:
+		h.Star4 = n
+	case 5:
+		h.Star5 = n
+	}
+}
+
+// 评分总数
+func (h *RatingHistogram) Total() int64 {
+	return h.Star1 + h.Star2 + h.Star3 + h.Star4 + h.Star5
+}
+
+// 市场查询状态
+type MarketStatus string
+
+const (
+	MarketOK         MarketStatus = "ok"          // 查询成功
+	MarketNotFound   MarketStatus = "not-found"   // 未上架或搜索不到
+	MarketHTTPError  MarketStatus = "http-error"  // 请求失败，包括被拦截
+	MarketParseError MarketStatus = "parse-error" // 页面结构变化，解析失败
+)
+
+// 单个市场的查询结果
+type MarketResult struct {
+	Status MarketStatus `bson:"status"` // 查询状态
+	Error  string       `bson:"error"`  // 错误信息
+	Err    error        `bson:"-"`      // 原始错误，可以用 errors.Is 判断 ErrNotFound 等
+}
+
+// 根据错误创建查询结果
+func newMarketResult(err error) *MarketResult {
+	result := &MarketResult{Status: MarketOK, Err: err}
+	if err == nil {
+		return result
+	}
+
+	result.Error = err.Error()
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		result.Status = MarketNotFound
+	case errors.Is(err, ErrLayoutChanged):
+		result.Status = MarketParseError
+	default:
+		result.Status = MarketHTTPError
+	}
+
+	return result
+}
+
 type APPData struct {
-	IOSData
-	// 华为市场
-	HWData
-	// 小米市场
-	MIData
-	// 应用宝
-	QQData
+	IOSID         string                   `bson:"ios_id"`         // ios id
+	PackageName   string                   `bson:"package_name"`   // android 包名
+	MarketIDs     map[string]string        `bson:"market_ids"`     // 查询各市场时使用的 id，key 为 Store.ID()
+	Markets       map[string]interface{}   `bson:"markets"`        // 各市场的数据，key 为 Store.ID()
+	MarketResults map[string]*MarketResult `bson:"market_results"` // 各市场的查询结果，key 为 Store.ID()
 }
 
+// 获取全部已注册市场的数据，先从 app store 拿到应用名称，再到其他市场搜索
+// 以包名为 id 的市场（小米、应用宝等）在其他市场查完后，用得到的包名查询
 func ParseAPPData(iosId string) (*APPData, error) {
-	var (
-		iosData *IOSData = new(IOSData)
-		hwData  *HWData  = new(HWData)
-		miData  *MIData  = new(MIData)
-		qqData  *QQData  = new(QQData)
-	)
+	return ParseAPPDataContext(context.Background(), iosId)
+}
+
+// 获取全部已注册市场的数据，ctx 取消或超时时中断全部请求
+func ParseAPPDataContext(ctx context.Context, iosId string) (*APPData, error) {
+	return DefaultClient.ParseAPPData(ctx, iosId)
+}
+
+// 获取全部已注册市场的数据
+func (c *Client) ParseAPPData(ctx context.Context, iosId string) (*APPData, error) {
+	ios := GetStore(StoreIOS)
+	if ios == nil {
+		return nil, errors.New("app store 市场未注册")
+	}
 
 	// IOS数据
-	iosData, err := ParseIOSData(iosId)
+	data, err := ios.Lookup(ctx, c, iosId)
 	if err != nil {
 		return nil, err
 	}
 
-	// 华为数据
-	hwId := getHWAppId(iosData.IOSName)
-	if hwId != "" {
-		hwData, _ = ParseHWData(hwId)
+	iosData, ok := data.(*IOSData)
+	if !ok {
+		return nil, errors.New("app store 市场返回的数据类型错误")
+	}
+
+	appData := &APPData{
+		IOSID:         iosId,
+		MarketIDs:     map[string]string{StoreIOS: iosId},
+		Markets:       map[string]interface{}{StoreIOS: iosData},
+		MarketResults: map[string]*MarketResult{StoreIOS: newMarketResult(nil)},
+	}
+
+	nameStores := make([]Store, 0)
+	pkgStores := make([]Store, 0)
+	for _, s := range Stores() {
+		if s.ID() == StoreIOS {
+			continue
+		}
+
+		if usesPackageName(s) {
+			pkgStores = append(pkgStores, s)
+		} else {
+			nameStores = append(nameStores, s)
+		}
+	}
+
+	// 按名称搜索的市场
+	c.lookupStores(ctx, appData, nameStores, func(s Store) (string, error) {
+		return s.Search(ctx, c, iosData.IOSName)
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// 小米数据
-	if hwId != "" {
-		miData, _ = ParseMIData(hwId)
+	// 以包名为 id 的市场
+	appData.PackageName = c.resolvePackageName(ctx, appData, nameStores, pkgStores, iosData.IOSName)
+	c.lookupStores(ctx, appData, pkgStores, func(s Store) (string, error) {
+		return appData.PackageName, nil
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// 应用宝数据
-	if hwId != "" {
-		qqData, _ = ParseQQData(hwId)
+	return appData, nil
+}
+
+// 同时查询多个市场，结果记录到 appData.MarketResults，idFunc 返回空时视为未上架
+func (c *Client) lookupStores(ctx context.Context, appData *APPData, list []Store, idFunc func(s Store) (string, error)) {
+	mu := sync.Mutex{}
+	tasks := make([]func(), 0)
+
+	for _, s := range list {
+		s := s
+		tasks = append(tasks, func() {
+			id, err := idFunc(s)
+			if err == nil && id == "" {
+				err = ErrNotFound
+			}
+
+			var data interface{}
+			if err == nil {
+				data, err = s.Lookup(ctx, c, id)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			appData.MarketResults[s.ID()] = newMarketResult(err)
+			if id != "" {
+				appData.MarketIDs[s.ID()] = id
+			}
+			if err == nil {
+				appData.Markets[s.ID()] = data
+			}
+		})
 	}
 
-	return &APPData{
-		*iosData,
-		*hwData,
-		*miData,
-		*qqData,
-	}, nil
+	c.parallel(tasks...)
+}
+
+// 确定 android 包名，优先用已查到的市场数据（例如华为的 HWPackageID），都没有时依次用以包名为 id 的市场搜索，跳过不支持搜索的市场
+func (c *Client) resolvePackageName(ctx context.Context, appData *APPData, nameStores []Store, pkgStores []Store, name string) string {
+	for _, s := range nameStores {
+		if v, ok := appData.Markets[s.ID()].(PackageNamer); ok && v.PackageName() != "" {
+			return v.PackageName()
+		}
+	}
+
+	for _, s := range pkgStores {
+		if !searchable(s) {
+			continue
+		}
+
+		id, err := s.Search(ctx, c, name)
+		if err == nil && id != "" {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// IOS数据，没有时返回空结构
+func (d *APPData) IOS() *IOSData {
+	if v, ok := d.Markets[StoreIOS].(*IOSData); ok {
+		return v
+	}
+	return new(IOSData)
+}
+
+// 华为数据，没有时返回空结构
+func (d *APPData) HW() *HWData {
+	if v, ok := d.Markets[StoreHW].(*HWData); ok {
+		return v
+	}
+	return new(HWData)
+}
+
+// 小米数据，没有时返回空结构
+func (d *APPData) MI() *MIData {
+	if v, ok := d.Markets[StoreMI].(*MIData); ok {
+		return v
+	}
+	return new(MIData)
+}
+
+// 应用宝数据，没有时返回空结构
+func (d *APPData) QQ() *QQData {
+	if v, ok := d.Markets[StoreQQ].(*QQData); ok {
+		return v
+	}
+	return new(QQData)
+}
+
+// google play 数据，没有时返回空结构
+func (d *APPData) GP() *GPData {
+	if v, ok := d.Markets[StoreGP].(*GPData); ok {
+		return v
+	}
+	return new(GPData)
+}
+
+// oppo 软件商店数据，没有时返回空结构
+func (d *APPData) OPPO() *OPPOData {
+	if v, ok := d.Markets[StoreOPPO].(*OPPOData); ok {
+		return v
+	}
+	return new(OPPOData)
+}
+
+// vivo 应用商店数据，没有时返回空结构
+func (d *APPData) Vivo() *VivoData {
+	if v, ok := d.Markets[StoreVivo].(*VivoData); ok {
+		return v
+	}
+	return new(VivoData)
+}
+
+// 荣耀应用市场数据，没有时返回空结构
+func (d *APPData) Honor() *HonorData {
+	if v, ok := d.Markets[StoreHonor].(*HonorData); ok {
+		return v
+	}
+	return new(HonorData)
+}
+
+// 三星 galaxy store 数据，没有时返回空结构
+func (d *APPData) Samsung() *SamsungData {
+	if v, ok := d.Markets[StoreSamsung].(*SamsungData); ok {
+		return v
+	}
+	return new(SamsungData)
+}
+
+// 360 手机助手数据，没有时返回空结构
+func (d *APPData) Qihoo() *QihooData {
+	if v, ok := d.Markets[StoreQihoo].(*QihooData); ok {
+		return v
+	}
+	return new(QihooData)
+}
+
+// 百度手机助手数据，没有时返回空结构
+func (d *APPData) Baidu() *BaiduData {
+	if v, ok := d.Markets[StoreBaidu].(*BaiduData); ok {
+		return v
+	}
+	return new(BaiduData)
+}
+
+// 豌豆荚数据，没有时返回空结构
+func (d *APPData) WDJ() *WDJData {
+	if v, ok := d.Markets[StoreWDJ].(*WDJData); ok {
+		return v
+	}
+	return new(WDJData)
 }
@@ -0,0 +1,72 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-08 10:05:44
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-08 10:05:44
+ * @Description:
+ */
+package parser
+
+import "strings"
+
+// 应用声明的权限
+type Permission struct {
+	Name        string `bson:"name"`        // 权限名称，例：android.permission.CAMERA 或 相机
+	Description string `bson:"description"` // 权限说明
+}
+
+// 两个权限列表的差异
+type PermissionDiff struct {
+	Added   []Permission `bson:"added"`   // 新增的权限
+	Removed []Permission `bson:"removed"` // 去掉的权限
+	Changed []Permission `bson:"changed"` // 说明有变化的权限，为新的说明
+}
+
+// 是否没有差异
+func (d *PermissionDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// 权限的 key，没有名称时用说明
+func (p Permission) key() string {
+	if p.Name != "" {
+		return strings.TrimSpace(p.Name)
+	}
+	return strings.TrimSpace(p.Description)
+}
+
+// 比较两个权限列表，before 为旧的，after 为新的，按名称匹配，结果保持列表中的顺序
+func DiffPermissions(before, after []Permission) *PermissionDiff {
+	diff := &PermissionDiff{
+		Added:   make([]Permission, 0),
+		Removed: make([]Permission, 0),
+		Changed: make([]Permission, 0),
+	}
+
+	oldMap := make(map[string]Permission)
+	for _, p := range before {
+		oldMap[p.key()] = p
+	}
+
+	newMap := make(map[string]Permission)
+	for _, p := range after {
+		newMap[p.key()] = p
+	}
+
+	for _, p := range after {
+		old, ok := oldMap[p.key()]
+		if !ok {
+			diff.Added = append(diff.Added, p)
+		} else if strings.TrimSpace(old.Description) != strings.TrimSpace(p.Description) {
+			diff.Changed = append(diff.Changed, p)
+		}
+	}
+
+	for _, p := range before {
+		if _, ok := newMap[p.key()]; !ok {
+			diff.Removed = append(diff.Removed, p)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,76 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-08-08 11:10:32
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-08-08 11:10:32
+ * @Description:
+ */
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	before := []Permission{
+		{Name: "相机", Description: "拍摄照片"},
+		{Name: "位置", Description: "获取粗略位置"},
+	}
+
+	after := []Permission{
+		{Name: "相机", Description: "拍摄照片和视频"},
+		{Name: "麦克风", Description: "录音"},
+	}
+
+	diff := DiffPermissions(before, after)
+
+	if len(diff.Added) != 1 || diff.Added[0].Name != "麦克风" {
+		t.Error("新增的权限错了", diff.Added)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "位置" {
+		t.Error("去掉的权限错了", diff.Removed)
+	}
+
+	if len(diff.Changed) != 1 || diff.Changed[0].Description != "拍摄照片和视频" {
+		t.Error("变化的权限错了", diff.Changed)
+	}
+
+	if !DiffPermissions(after, after).IsEmpty() {
+		t.Error("相同的列表不应该有差异")
+	}
+}
+
+func TestGetHWPermissions(t *testing.T) {
+	json := gjson.Parse(`{"layoutData": [
+		{"dataList": [{"name": "抖音"}]},
+		{"dataList": [{"permissions": [{"name": "相机", "desc": "拍摄照片和视频"}, {"name": "麦克风", "desc": "录音"}]}]}
+	]}`)
+
+	list := getHWPermissions(&json)
+	if len(list) != 2 || list[0] != (Permission{Name: "相机", Description: "拍摄照片和视频"}) {
+		t.Error("权限取错了", list)
+	}
+}
+
+func TestGetMIPermissions(t *testing.T) {
+	html := `<ul class="second-ul">
+		<li>• 相机：拍摄照片和视频</li>
+		<li>• 访问网络</li>
+		<li> </li>
+	</ul>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := getMIPermissions(doc)
+	if len(list) != 2 || list[0] != (Permission{Name: "相机", Description: "拍摄照片和视频"}) || list[1].Name != "访问网络" {
+		t.Error("权限取错了", list)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-27 10:20:36
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-27 10:20:36
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 360 手机助手，市场内的 id 为 soft_id
+type QihooData struct {
+	QihooExist         bool   `bson:"qihoo_exist"`          // 360 是否有
+	QihooID            string `bson:"qihoo_id"`             // 360 soft id
+	QihooPackageID     string `bson:"qihoo_package_id"`     // 360 package id
+	QihooName          string `bson:"qihoo_name"`           // 360 名称
+	QihooSupplier      string `bson:"qihoo_supplier"`       // 360 开发者
+	QihooDownloadCount string `bson:"qihoo_download_count"` // 360 下载量
+	QihooLastVersion   string `bson:"qihoo_last_version"`   // 360 最新版本
+	QihooLastUpdate    string `bson:"qihoo_last_update"`    // 360 最新版本时间
+
+	QihooDownloadCountValue int64     `bson:"qihoo_download_count_value"` // 360 下载量，数字
+	QihooLastUpdateTime     time.Time `bson:"qihoo_last_update_time"`     // 360 最新版本时间
+}
+
+// 包名
+func (d *QihooData) PackageName() string {
+	return d.QihooPackageID
+}
+
+// 获取 360 手机助手数据，id 为 soft_id，可以用 Search 按名称获取
+func ParseQihooData(id string) (*QihooData, error) {
+	return ParseQihooDataContext(context.Background(), id)
+}
+
+// 获取 360 手机助手数据，ctx 取消或超时时中断请求
+func ParseQihooDataContext(ctx context.Context, id string) (*QihooData, error) {
+	return DefaultClient.ParseQihooData(ctx, id)
+}
+
+// 获取 360 手机助手数据
+func (c *Client) ParseQihooData(ctx context.Context, id string) (*QihooData, error) {
+	// 创建 qihoo data 结构体
+	qihooData := new(QihooData)
+
+	if strings.TrimSpace(id) == "" {
+		return qihooData, errors.New("id 不能为空")
+	}
+
+	doc, err := c.getQihooDoc(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		// 下架的应用返回 404
+		return qihooData, nil
+	}
+	if err != nil {
+		return qihooData, err
+	}
+
+	qihooData.QihooExist = getQihooExist(doc)
+	if qihooData.QihooExist {
+		qihooData.QihooID = id
+		qihooData.QihooPackageID = getQihooPackageID(doc)
+		qihooData.QihooName = getQihooName(doc)
+		qihooData.QihooSupplier = getQihooSupplier(doc)
+		qihooData.QihooDownloadCount = getQihooDownloadCount(doc)
+		qihooData.QihooLastVersion = getQihooLastVersion(doc)
+		qihooData.QihooLastUpdate = getQihooLastUpdate(doc)
+		qihooData.QihooDownloadCountValue = normalizeCount(qihooData.QihooDownloadCount)
+		qihooData.QihooLastUpdateTime = normalizeLocalDate(qihooData.QihooLastUpdate)
+
+		if qihooData.QihooName == "" {
+			return qihooData, layoutError("名称")
+		}
+	}
+
+	return qihooData, nil
+}
+
+// 360 手机助手
+type qihooStore struct{}
+
+func (qihooStore) ID() string {
+	return StoreQihoo
+}
+
+func (qihooStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	qihooData, err := c.ParseQihooData(ctx, id)
+	if err != nil {
+		return qihooData, err
+	}
+
+	if !qihooData.QihooExist {
+		return qihooData, ErrNotFound
+	}
+
+	return qihooData, nil
+}
+
+func (qihooStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	doc, err := c.getQihooSearchDoc(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return getQihooSearchID(doc, name), nil
+}
+
+func (qihooStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	qihooData, err := c.ParseQihooData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return qihooData.QihooExist, nil
+}
+
+func (c *Client) getQihooDoc(ctx context.Context, id string) (*goquery.Document, error) {
+	u := "https://zhushou.360.cn/detail/index/soft_id/" + url.PathEscape(id)
+	return c.getQihooPage(ctx, u)
+}
+
+// 360 手机助手搜索结果页面的 doc
+func (c *Client) getQihooSearchDoc(ctx context.Context, name string) (*goquery.Document, error) {
+	params := url.Values{}
+	params.Add("kw", name)
+
+	u := "https://zhushou.360.cn/search/index/?" + params.Encode()
+	return c.getQihooPage(ctx, u)
+}
+
+func (c *Client) getQihooPage(ctx context.Context, u string) (*goquery.Document, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 搜索结果中详情页的链接，例：/detail/index/soft_id/3137592
+var qihooSoftIDReg = regexp.MustCompile(`/soft_id/([0-9]+)`)
+
+// 从搜索结果中获取 soft id，只取第一个结果，名称不匹配时返回空
+func getQihooSearchID(doc *goquery.Document, name string) string {
+	node := doc.Find(".SeaCon li dl dd h3 a").First()
+	findName := strings.TrimSpace(node.AttrOr("title", node.Text()))
+
+	// 判断名称是否匹配
+	if findName == "" || !strings.Contains(name, findName) {
+		return ""
+	}
+
+	m := qihooSoftIDReg.FindStringSubmatch(node.AttrOr("href", ""))
+	if m == nil {
+		return ""
+	}
+
+	return m[1]
+}
+
+// 判断是否在 360 手机助手上架
+func getQihooExist(doc *goquery.Document) bool {
+	node := doc.Find("#app-name")
+	return node.Length() > 0
+}
+
+// 页面脚本中的包名，例：'pname': "com.ss.android.ugc.aweme"
+var qihooPackageReg = regexp.MustCompile(`'pname'\s*:\s*"([^"]+)"`)
+
+// 获取包名，取不到时返回空
+func getQihooPackageID(doc *goquery.Document) string {
+	pkg := ""
+
+	doc.Find("script").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		m := qihooPackageReg.FindStringSubmatch(s.Text())
+		if m != nil {
+			pkg = m[1]
+			return false
+		}
+		return true
+	})
+
+	return strings.TrimSpace(pkg)
+}
+
+// 获取名称
+func getQihooName(doc *goquery.Document) string {
+	node := doc.Find("#app-name span").First()
+	return strings.TrimSpace(node.AttrOr("title", node.Text()))
+}
+
+// 获取下载量，例：下载：5亿次
+func getQihooDownloadCount(doc *goquery.Document) string {
+	node := doc.Find(".pf .s-3").First()
+	txt := node.Text()
+	txt = strings.ReplaceAll(txt, "下载：", "")
+	txt = strings.ReplaceAll(txt, "次", "")
+	return strings.TrimSpace(txt)
+}
+
+// 获取开发者
+func getQihooSupplier(doc *goquery.Document) string {
+	return getQihooAttr(doc, "作者")
+}
+
+// 获取最新版本号
+func getQihooLastVersion(doc *goquery.Document) string {
+	return getQihooAttr(doc, "版本")
+}
+
+// 获取最新更新时间
+func getQihooLastUpdate(doc *goquery.Document) string {
+	return getQihooAttr(doc, "更新时间")
+}
+
+// 获取基本信息中的一项，例：<td><strong>版本：</strong>26.8.0</td>
+func getQihooAttr(doc *goquery.Document, label string) string {
+	value := ""
+
+	doc.Find(".base-info td").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		title := strings.TrimSpace(s.Find("strong").Text())
+		if strings.TrimRight(title, "：: ") != label {
+			return true
+		}
+
+		value = strings.TrimPrefix(strings.TrimSpace(s.Text()), title)
+		return false
+	})
+
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,93 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-27 11:02:51
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-27 11:02:51
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+var QIHOO_APP_ID = "3137592"
+
+func TestGetQihooExist(t *testing.T) {
+	doc, err := DefaultClient.getQihooDoc(context.Background(), QIHOO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !getQihooExist(doc) {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetQihooName(t *testing.T) {
+	doc, err := DefaultClient.getQihooDoc(context.Background(), QIHOO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getQihooName(doc)
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetQihooLastVersion(t *testing.T) {
+	doc, err := DefaultClient.getQihooDoc(context.Background(), QIHOO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getQihooLastVersion(doc)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetQihooDownloadCount(t *testing.T) {
+	doc, err := DefaultClient.getQihooDoc(context.Background(), QIHOO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	count := getQihooDownloadCount(doc)
+
+	if normalizeCount(count) == 0 {
+		t.Error("count 取错了")
+	}
+}
+
+func TestQihooNotFound(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: transportFunc(func(req *http.Request) (int, string) {
+		return 404, ""
+	})})
+
+	qihooData, err := c.ParseQihooData(context.Background(), "1")
+	if err != nil || qihooData.QihooExist {
+		t.Error("404 应该视为未上架", err)
+	}
+
+	exist, err := GetStore(StoreQihoo).Exists(context.Background(), c, "1")
+	if err != nil || exist {
+		t.Error("404 应该视为不存在", err)
+	}
+
+	if _, err := GetStore(StoreQihoo).Lookup(context.Background(), c, "1"); !errors.Is(err, ErrNotFound) {
+		t.Error("Lookup 应该返回 ErrNotFound", err)
+	}
+}
@@ -8,10 +8,12 @@
 package parser
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -23,10 +25,28 @@ type QQData struct {
 	QQPackageID   string `bson:"qq_package_id"`   // qq package id
 	QQLastVersion string `bson:"qq_last_version"` // QQ 最新版本
 	QQLastUpdate  string `bson:"qq_last_update"`  // QQ 最新版本时间
+	QQMedia       Media  `bson:"qq_media"`        // QQ 截图与预览视频
+
+	QQLastUpdateTime time.Time `bson:"qq_last_update_time"` // QQ 最新版本时间
+}
+
+// 包名
+func (d *QQData) PackageName() string {
+	return d.QQPackageID
 }
 
 // 获取应用宝数据
 func ParseQQData(pkgId string) (*QQData, error) {
+	return ParseQQDataContext(context.Background(), pkgId)
+}
+
+// 获取应用宝数据，ctx 取消或超时时中断请求
+func ParseQQDataContext(ctx context.Context, pkgId string) (*QQData, error) {
+	return DefaultClient.ParseQQData(ctx, pkgId)
+}
+
+// 获取应用宝数据
+func (c *Client) ParseQQData(ctx context.Context, pkgId string) (*QQData, error) {
 	// 创建 qq data 结构体
 	qqData := new(QQData)
 
@@ -34,7 +54,7 @@ func ParseQQData(pkgId string) (*QQData, error) {
 		return qqData, errors.New("pkgId 不能为空")
 	}
 
-	doc, err := getQQDoc(pkgId)
+	doc, err := c.getQQDoc(ctx, pkgId)
 	if err != nil {
 		return qqData, err
 	}
@@ -45,29 +65,105 @@ func ParseQQData(pkgId string) (*QQData, error) {
 		qqData.QQName = getQQName(doc)
 		qqData.QQLastVersion = getQQLastVersion(doc)
 		qqData.QQLastUpdate = getQQLastUpdate(doc)
+		qqData.QQMedia = getQQMedia(doc)
+		qqData.QQLastUpdateTime = normalizeLocalDate(qqData.QQLastUpdate)
+
+		if qqData.QQName == "" {
+			return qqData, layoutError("名称")
+		}
 	}
 
 	return qqData, nil
 }
 
-func getQQDoc(id string) (*goquery.Document, error) {
-	url := "https://sj.qq.com/appdetail/" + id
-	client := &http.Client{}
+// 应用宝
+type qqStore struct{}
+
+func (qqStore) ID() string {
+	return StoreQQ
+}
+
+func (qqStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	qqData, err := c.ParseQQData(ctx, id)
+	if err != nil {
+		return qqData, err
+	}
+
+	if !qqData.QQExist {
+		return qqData, ErrNotFound
+	}
+
+	return qqData, nil
+}
+
+func (qqStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	doc, err := c.getQQSearchDoc(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return getQQSearchID(doc, name), nil
+}
+
+func (qqStore) UsesPackageName() bool {
+	return true
+}
+
+func (qqStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	doc, err := c.getQQDoc(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getQQExist(doc), nil
+}
+
+func (c *Client) getQQDoc(ctx context.Context, id string) (*goquery.Document, error) {
+	u := "https://sj.qq.com/appdetail/" + id
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 应用宝搜索结果页面的 doc
+func (c *Client) getQQSearchDoc(ctx context.Context, name string) (*goquery.Document, error) {
+	u := "https://sj.qq.com/search?q=" + url.QueryEscape(name)
 
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("返回状态错误 %v", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Load the HTML document
@@ -79,6 +175,21 @@ func getQQDoc(id string) (*goquery.Document, error) {
 	return doc, nil
 }
 
+// 从搜索结果中获取包名，只取第一个结果，名称不匹配时返回空
+func getQQSearchID(doc *goquery.Document, name string) string {
+	node := doc.Find("a[href^='/appdetail/']").First()
+	findName := strings.TrimSpace(node.Find("h2, h3, h4").First().Text())
+
+	// 判断名称是否匹配
+	if findName == "" || !strings.Contains(name, findName) {
+		return ""
+	}
+
+	// 例：/appdetail/com.ss.android.ugc.aweme
+	href := node.AttrOr("href", "")
+	return strings.TrimPrefix(href, "/appdetail/")
+}
+
 // 判断是否在QQ市场上架
 func getQQExist(doc *goquery.Document) bool {
 	if doc == nil {
@@ -130,3 +241,27 @@ func getQQLastUpdate(doc *goquery.Document) string {
 
 	return update
 }
+
+// 获取截图与预览视频，样式名带有编译后的后缀，按前缀匹配
+func getQQMedia(doc *goquery.Document) Media {
+	media := Media{
+		Screenshots: make([]*Screenshot, 0),
+		Videos:      make([]*Video, 0),
+	}
+
+	doc.Find("[class^='GameDetail_screenshot'] img").Each(func(i int, s *goquery.Selection) {
+		u := strings.TrimSpace(s.AttrOr("src", ""))
+		if u != "" {
+			media.Screenshots = append(media.Screenshots, &Screenshot{URL: u})
+		}
+	})
+
+	doc.Find("[class^='GameDetail_screenshot'] video").Each(func(i int, s *goquery.Selection) {
+		u := strings.TrimSpace(s.AttrOr("src", ""))
+		if u != "" {
+			media.Videos = append(media.Videos, &Video{URL: u, Poster: strings.TrimSpace(s.AttrOr("poster", ""))})
+		}
+	})
+
+	return media
+}
@@ -8,6 +8,7 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"testing"
 )
@@ -15,7 +16,7 @@ import (
 var QQ_APP_ID = "com.ss.android.ugc.aweme"
 
 func TestGetQQExist(t *testing.T) {
-	doc, err := getQQDoc(QQ_APP_ID)
+	doc, err := DefaultClient.getQQDoc(context.Background(), QQ_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -26,7 +27,7 @@ func TestGetQQExist(t *testing.T) {
 		t.Error("exist 取错了")
 	}
 
-	doc, _ = getQQDoc(QQ_APP_ID + "fake")
+	doc, _ = DefaultClient.getQQDoc(context.Background(), QQ_APP_ID+"fake")
 
 	exist = getQQExist(doc)
 	if exist == true {
@@ -35,7 +36,7 @@ func TestGetQQExist(t *testing.T) {
 }
 
 func TestGetQQName(t *testing.T) {
-	doc, err := getQQDoc(QQ_APP_ID)
+	doc, err := DefaultClient.getQQDoc(context.Background(), QQ_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -49,7 +50,7 @@ func TestGetQQName(t *testing.T) {
 }
 
 func TestGetQQLastVersion(t *testing.T) {
-	doc, err := getQQDoc(QQ_APP_ID)
+	doc, err := DefaultClient.getQQDoc(context.Background(), QQ_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -64,7 +65,7 @@ func TestGetQQLastVersion(t *testing.T) {
 }
 
 func TestGetQQLastUpdate(t *testing.T) {
-	doc, err := getQQDoc(QQ_APP_ID)
+	doc, err := DefaultClient.getQQDoc(context.Background(), QQ_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -77,3 +78,17 @@ func TestGetQQLastUpdate(t *testing.T) {
 		t.Error("update 取错了")
 	}
 }
+
+func TestGetQQSearchID(t *testing.T) {
+	doc, err := DefaultClient.getQQSearchDoc(context.Background(), "抖音")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	id := getQQSearchID(doc, "抖音")
+
+	if id != QQ_APP_ID {
+		t.Error("id 取错了")
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-26 16:05:44
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-26 16:05:44
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// 三星 galaxy store
+type SamsungData struct {
+	SamsungExist       bool   `bson:"samsung_exist"`        // samsung 是否有
+	SamsungPackageID   string `bson:"samsung_package_id"`   // samsung package id
+	SamsungName        string `bson:"samsung_name"`         // samsung 名称
+	SamsungSupplier    string `bson:"samsung_supplier"`     // samsung 卖家
+	SamsungRate        string `bson:"samsung_rate"`         // samsung 评分
+	SamsungRateCount   string `bson:"samsung_rate_count"`   // samsung 评价数
+	SamsungLastVersion string `bson:"samsung_last_version"` // samsung 最新版本
+	SamsungLastUpdate  string `bson:"samsung_last_update"`  // samsung 最新版本时间
+	SamsungPackageSize string `bson:"samsung_package_size"` // samsung 包大小，单位字节
+
+	SamsungRateValue      float64   `bson:"samsung_rate_value"`       // samsung 评分，数字
+	SamsungRateCountValue int64     `bson:"samsung_rate_count_value"` // samsung 评价数，数字
+	SamsungPackageBytes   int64     `bson:"samsung_package_bytes"`    // samsung 包大小，字节数
+	SamsungLastUpdateTime time.Time `bson:"samsung_last_update_time"` // samsung 最新版本时间
+}
+
+// 包名
+func (d *SamsungData) PackageName() string {
+	return d.SamsungPackageID
+}
+
+// 获取三星 galaxy store数据
+func ParseSamsungData(pkgId string) (*SamsungData, error) {
+	return ParseSamsungDataContext(context.Background(), pkgId)
+}
+
+// 获取三星 galaxy store数据，ctx 取消或超时时中断请求
+func ParseSamsungDataContext(ctx context.Context, pkgId string) (*SamsungData, error) {
+	return DefaultClient.ParseSamsungData(ctx, pkgId)
+}
+
+// 获取三星 galaxy store数据
+func (c *Client) ParseSamsungData(ctx context.Context, pkgId string) (*SamsungData, error) {
+	// 创建 samsung data 结构体
+	samsungData := new(SamsungData)
+
+	if strings.TrimSpace(pkgId) == "" {
+		return samsungData, errors.New("pkgId 不能为空")
+	}
+
+	json, err := c.getSamsungAppData(ctx, pkgId)
+	if err != nil {
+		return samsungData, err
+	}
+
+	samsungData.SamsungExist = getSamsungExist(json)
+	if samsungData.SamsungExist {
+		samsungData.SamsungPackageID = pkgId
+		samsungData.SamsungName = getSamsungName(json)
+		samsungData.SamsungSupplier = getSamsungSupplier(json)
+		samsungData.SamsungRate = getSamsungRate(json)
+		samsungData.SamsungRateCount = getSamsungRateCount(json)
+		samsungData.SamsungLastVersion = getSamsungLastVersion(json)
+		samsungData.SamsungLastUpdate = getSamsungLastUpdate(json)
+		samsungData.SamsungPackageSize = getSamsungPackageSize(json)
+		samsungData.SamsungRateValue = normalizeRate(samsungData.SamsungRate)
+		samsungData.SamsungRateCountValue = normalizeCount(samsungData.SamsungRateCount)
+		samsungData.SamsungPackageBytes = normalizeSize(samsungData.SamsungPackageSize)
+		samsungData.SamsungLastUpdateTime = normalizeDate(samsungData.SamsungLastUpdate)
+
+		if samsungData.SamsungName == "" {
+			return samsungData, layoutError("名称")
+		}
+	}
+
+	return samsungData, nil
+}
+
+// 三星 galaxy store，网页版没有搜索
+type samsungStore struct {
+	unsearchable
+}
+
+func (samsungStore) ID() string {
+	return StoreSamsung
+}
+
+func (samsungStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	samsungData, err := c.ParseSamsungData(ctx, id)
+	if err != nil {
+		return samsungData, err
+	}
+
+	if !samsungData.SamsungExist {
+		return samsungData, ErrNotFound
+	}
+
+	return samsungData, nil
+}
+
+func (samsungStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	json, err := c.getSamsungAppData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getSamsungExist(json), nil
+}
+
+func (samsungStore) UsesPackageName() bool {
+	return true
+}
+
+// 三星 galaxy store 网页版的详情接口
+func (c *Client) getSamsungAppData(ctx context.Context, pkgId string) (*gjson.Result, error) {
+	u := "https://galaxystore.samsung.com/api/detail/" + url.PathEscape(pkgId)
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	json := gjson.ParseBytes(bytes)
+	return &json, nil
+}
+
+// 判断是否在三星 galaxy store上架
+func getSamsungExist(json *gjson.Result) bool {
+	return json.Get("DetailMain.contentName").Exists()
+}
+
+// 获取名称
+func getSamsungName(json *gjson.Result) string {
+	name := json.Get("DetailMain.contentName")
+	return strings.TrimSpace(name.String())
+}
+
+// 获取卖家
+func getSamsungSupplier(json *gjson.Result) string {
+	supplier := json.Get("SellerInfo.sellerName")
+	return strings.TrimSpace(supplier.String())
+}
+
+// 获取评分
+func getSamsungRate(json *gjson.Result) string {
+	rate := json.Get("DetailMain.ratingNum")
+	return strings.TrimSpace(rate.String())
+}
+
+// 获取评价数量
+func getSamsungRateCount(json *gjson.Result) string {
+	count := json.Get("commentListTotalCount")
+	return strings.TrimSpace(count.String())
+}
+
+// 获取版本信息
+func getSamsungLastVersion(json *gjson.Result) string {
+	version := json.Get("DetailMain.contentBinaryVersion")
+	return strings.TrimSpace(version.String())
+}
+
+// 获取版本更新时间，例：20230703
+func getSamsungLastUpdate(json *gjson.Result) string {
+	update := json.Get("DetailMain.lastUpdateDate")
+	return strings.TrimSpace(update.String())
+}
+
+// 获取包大小，单位字节
+func getSamsungPackageSize(json *gjson.Result) string {
+	size := json.Get("DetailMain.contentBinarySize")
+	return strings.TrimSpace(size.String())
+}
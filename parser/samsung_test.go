@@ -0,0 +1,81 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-26 16:40:12
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-26 16:40:12
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var SAMSUNG_APP_ID = "com.ss.android.ugc.trill"
+
+func TestGetSamsungExist(t *testing.T) {
+	json, err := DefaultClient.getSamsungAppData(context.Background(), SAMSUNG_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !getSamsungExist(json) {
+		t.Error("exist 取错了")
+	}
+
+	json, err = DefaultClient.getSamsungAppData(context.Background(), SAMSUNG_APP_ID+"fake")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if getSamsungExist(json) {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetSamsungName(t *testing.T) {
+	json, err := DefaultClient.getSamsungAppData(context.Background(), SAMSUNG_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getSamsungName(json)
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetSamsungLastVersion(t *testing.T) {
+	json, err := DefaultClient.getSamsungAppData(context.Background(), SAMSUNG_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getSamsungLastVersion(json)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetSamsungLastUpdate(t *testing.T) {
+	json, err := DefaultClient.getSamsungAppData(context.Background(), SAMSUNG_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	update := getSamsungLastUpdate(json)
+
+	if normalizeDate(update).IsZero() {
+		t.Error("update 取错了")
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-10 10:12:40
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-10 10:12:40
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"sync"
+)
+
+// 内置市场的 id
+const (
+	StoreIOS     = "ios"     // app store
+	StoreHW      = "hw"      // 华为市场
+	StoreMI      = "mi"      // 小米市场
+	StoreQQ      = "qq"      // 应用宝
+	StoreGP      = "gp"      // google play
+	StoreOPPO    = "oppo"    // oppo 软件商店
+	StoreVivo    = "vivo"    // vivo 应用商店
+	StoreHonor   = "honor"   // 荣耀应用市场
+	StoreSamsung = "samsung" // 三星 galaxy store
+	StoreQihoo   = "qihoo"   // 360 手机助手
+	StoreBaidu   = "baidu"   // 百度手机助手
+	StoreWDJ     = "wdj"     // 豌豆荚
+)
+
+// 应用市场
+type Store interface {
+	// 市场 id，同时作为 APPData.Markets 的 key
+	ID() string
+	// 根据市场内的 id 获取应用数据，请求统一使用 c 的 http client
+	Lookup(ctx context.Context, c *Client, id string) (interface{}, error)
+	// 根据应用名称获取市场内的 id，找不到时返回空字符串
+	Search(ctx context.Context, c *Client, name string) (string, error)
+	// 判断应用是否在市场上架
+	Exists(ctx context.Context, c *Client, id string) (bool, error)
+}
+
+// 以 Android 包名作为市场内 id 的市场，ParseAPPData 会先确定包名再查询这类市场
+type PackageStore interface {
+	Store
+	UsesPackageName() bool
+}
+
+// 市场返回的数据带有包名时实现该接口，ParseAPPData 会用它确定包名
+type PackageNamer interface {
+	PackageName() string
+}
+
+// 不支持按名称搜索的市场实现该接口并返回 false，Search 返回 ErrSearchUnsupported，ParseAPPData 确定包名时跳过这类市场
+type SearchableStore interface {
+	Store
+	Searchable() bool
+}
+
+// 判断市场是否以包名作为 id
+func usesPackageName(s Store) bool {
+	ps, ok := s.(PackageStore)
+	return ok && ps.UsesPackageName()
+}
+
+// 不支持按名称搜索的市场嵌入该类型
+type unsearchable struct{}
+
+func (unsearchable) Search(ctx context.Context, c *Client, name string) (string, error) {
+	return "", ErrSearchUnsupported
+}
+
+func (unsearchable) Searchable() bool {
+	return false
+}
+
+// 判断市场是否支持按名称搜索，没有实现 SearchableStore 的视为支持
+func searchable(s Store) bool {
+	ss, ok := s.(SearchableStore)
+	return !ok || ss.Searchable()
+}
+
+var (
+	storesMu sync.RWMutex
+	stores   []Store
+)
+
+// 注册市场，id 相同时替换已注册的市场
+func RegisterStore(s Store) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	for i, v := range stores {
+		if v.ID() == s.ID() {
+			stores[i] = s
+			return
+		}
+	}
+
+	stores = append(stores, s)
+}
+
+// 根据 id 获取已注册的市场，没有时返回 nil
+func GetStore(id string) Store {
+	storesMu.RLock()
+	defer storesMu.RUnlock()
+
+	for _, v := range stores {
+		if v.ID() == id {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// 获取全部已注册的市场，按注册顺序排列
+func Stores() []Store {
+	storesMu.RLock()
+	defer storesMu.RUnlock()
+
+	list := make([]Store, len(stores))
+	copy(list, stores)
+
+	return list
+}
+
+func init() {
+	RegisterStore(iosStore{})
+	RegisterStore(hwStore{})
+	RegisterStore(miStore{})
+	RegisterStore(qqStore{})
+	RegisterStore(gpStore{})
+	RegisterStore(oppoStore{})
+	RegisterStore(vivoStore{})
+	RegisterStore(honorStore{})
+	RegisterStore(samsungStore{})
+	RegisterStore(qihooStore{})
+	RegisterStore(baiduStore{})
+	RegisterStore(wdjStore{})
+}
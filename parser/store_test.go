@@ -0,0 +1,124 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-10 10:40:12
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-10 10:40:12
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeStore struct {
+	id string
+}
+
+func (s fakeStore) ID() string { return s.id }
+func (s fakeStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	return id, nil
+}
+func (s fakeStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	return name, nil
+}
+func (s fakeStore) Exists(ctx context.Context, c *Client, id string) (bool, error) { return true, nil }
+
+func TestBuiltinStores(t *testing.T) {
+	for _, id := range []string{StoreIOS, StoreHW, StoreMI, StoreQQ, StoreGP, StoreOPPO, StoreVivo, StoreHonor, StoreSamsung, StoreQihoo, StoreBaidu, StoreWDJ} {
+		if GetStore(id) == nil {
+			t.Errorf("%v 市场未注册", id)
+		}
+	}
+}
+
+func TestRegisterStore(t *testing.T) {
+	RegisterStore(fakeStore{id: "fake"})
+	defer func() {
+		storesMu.Lock()
+		stores = stores[:len(stores)-1]
+		storesMu.Unlock()
+	}()
+
+	count := len(Stores())
+
+	// 相同 id 重复注册时替换
+	RegisterStore(fakeStore{id: "fake"})
+	if len(Stores()) != count {
+		t.Error("重复注册了相同 id 的市场")
+	}
+
+	if GetStore("fake") == nil {
+		t.Error("没找到注册的市场")
+	}
+}
+
+// 以包名为 id 的市场
+type fakePackageStore struct {
+	fakeStore
+}
+
+func (s fakePackageStore) UsesPackageName() bool { return true }
+
+// 不支持搜索的市场，调用 Search 时测试失败
+type fakeUnsearchableStore struct {
+	fakePackageStore
+	t *testing.T
+}
+
+func (s fakeUnsearchableStore) Searchable() bool { return false }
+func (s fakeUnsearchableStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	s.t.Error("不应该调用不支持搜索的市场")
+	return "", ErrSearchUnsupported
+}
+
+func TestResolvePackageName(t *testing.T) {
+	hw := fakeStore{id: StoreHW}
+	pkg := fakePackageStore{fakeStore{id: "fake"}}
+
+	if !usesPackageName(pkg) || usesPackageName(hw) {
+		t.Error("usesPackageName 判断错了")
+	}
+
+	// 华为有包名时直接使用
+	appData := &APPData{Markets: map[string]interface{}{
+		StoreHW: &HWData{HWPackageID: "com.ss.android.ugc.aweme"},
+	}}
+	name := DefaultClient.resolvePackageName(context.Background(), appData, []Store{hw}, []Store{pkg}, "抖音")
+	if name != "com.ss.android.ugc.aweme" {
+		t.Error("没有使用华为的包名")
+	}
+
+	// 华为没有时用以包名为 id 的市场搜索
+	appData = &APPData{Markets: map[string]interface{}{}}
+	name = DefaultClient.resolvePackageName(context.Background(), appData, []Store{hw}, []Store{pkg}, "抖音")
+	if name != "抖音" {
+		t.Error("没有使用搜索得到的包名")
+	}
+
+	// 跳过不支持搜索的市场
+	unsearchable := fakeUnsearchableStore{fakePackageStore{fakeStore{id: "nosearch"}}, t}
+	if searchable(unsearchable) || !searchable(pkg) {
+		t.Error("searchable 判断错了")
+	}
+
+	name = DefaultClient.resolvePackageName(context.Background(), appData, []Store{hw}, []Store{unsearchable, pkg}, "抖音")
+	if name != "抖音" {
+		t.Error("没有跳过不支持搜索的市场")
+	}
+}
+
+func TestBuiltinUnsearchableStores(t *testing.T) {
+	for _, id := range []string{StoreGP, StoreOPPO, StoreVivo, StoreHonor, StoreSamsung} {
+		s := GetStore(id)
+		if searchable(s) {
+			t.Errorf("%v 不支持搜索", id)
+		}
+
+		if _, err := s.Search(context.Background(), DefaultClient, "抖音"); !errors.Is(err, ErrSearchUnsupported) {
+			t.Errorf("%v 应该返回 ErrSearchUnsupported", id)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-24 14:30:26
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-24 14:30:26
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// vivo 应用商店
+type VivoData struct {
+	VivoExist         bool   `bson:"vivo_exist"`          // vivo 是否有
+	VivoPackageID     string `bson:"vivo_package_id"`     // vivo package id
+	VivoName          string `bson:"vivo_name"`           // vivo 名称
+	VivoSupplier      string `bson:"vivo_supplier"`       // vivo 开发者
+	VivoRate          string `bson:"vivo_rate"`           // vivo 评分
+	VivoDownloadCount string `bson:"vivo_download_count"` // vivo 下载量
+	VivoLastVersion   string `bson:"vivo_last_version"`   // vivo 最新版本
+	VivoLastUpdate    string `bson:"vivo_last_update"`    // vivo 最新版本时间
+	VivoPackageSize   string `bson:"vivo_package_size"`   // vivo 包大小，单位 KB
+
+	VivoRateValue          float64   `bson:"vivo_rate_value"`           // vivo 评分，数字
+	VivoDownloadCountValue int64     `bson:"vivo_download_count_value"` // vivo 下载量，数字
+	VivoPackageBytes       int64     `bson:"vivo_package_bytes"`        // vivo 包大小，字节数
+	VivoLastUpdateTime     time.Time `bson:"vivo_last_update_time"`     // vivo 最新版本时间
+}
+
+// 包名
+func (d *VivoData) PackageName() string {
+	return d.VivoPackageID
+}
+
+// 获取 vivo 应用商店数据
+func ParseVivoData(pkgId string) (*VivoData, error) {
+	return ParseVivoDataContext(context.Background(), pkgId)
+}
+
+// 获取 vivo 应用商店数据，ctx 取消或超时时中断请求
+func ParseVivoDataContext(ctx context.Context, pkgId string) (*VivoData, error) {
+	return DefaultClient.ParseVivoData(ctx, pkgId)
+}
+
+// 获取 vivo 应用商店数据
+func (c *Client) ParseVivoData(ctx context.Context, pkgId string) (*VivoData, error) {
+	// 创建 vivo data 结构体
+	vivoData := new(VivoData)
+
+	if strings.TrimSpace(pkgId) == "" {
+		return vivoData, errors.New("pkgId 不能为空")
+	}
+
+	json, err := c.getVivoAppData(ctx, pkgId)
+	if err != nil {
+		return vivoData, err
+	}
+
+	vivoData.VivoExist = getVivoExist(json)
+	if vivoData.VivoExist {
+		vivoData.VivoPackageID = pkgId
+		vivoData.VivoName = getVivoName(json)
+		vivoData.VivoSupplier = getVivoSupplier(json)
+		vivoData.VivoRate = getVivoRate(json)
+		vivoData.VivoDownloadCount = getVivoDownloadCount(json)
+		vivoData.VivoLastVersion = getVivoLastVersion(json)
+		vivoData.VivoLastUpdate = getVivoLastUpdate(json)
+		vivoData.VivoPackageSize = getVivoPackageSize(json)
+		vivoData.VivoRateValue = normalizeRate(vivoData.VivoRate)
+		vivoData.VivoDownloadCountValue = normalizeCount(vivoData.VivoDownloadCount)
+		vivoData.VivoPackageBytes = normalizeSizeUnit(vivoData.VivoPackageSize, "KiB")
+		vivoData.VivoLastUpdateTime = normalizeLocalDate(vivoData.VivoLastUpdate)
+
+		if vivoData.VivoName == "" {
+			return vivoData, layoutError("名称")
+		}
+	}
+
+	return vivoData, nil
+}
+
+// vivo 应用商店，网页版没有搜索
+type vivoStore struct {
+	unsearchable
+}
+
+func (vivoStore) ID() string {
+	return StoreVivo
+}
+
+func (vivoStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	vivoData, err := c.ParseVivoData(ctx, id)
+	if err != nil {
+		return vivoData, err
+	}
+
+	if !vivoData.VivoExist {
+		return vivoData, ErrNotFound
+	}
+
+	return vivoData, nil
+}
+
+func (vivoStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	json, err := c.getVivoAppData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getVivoExist(json), nil
+}
+
+func (vivoStore) UsesPackageName() bool {
+	return true
+}
+
+// vivo 应用商店 h5 详情接口
+func (c *Client) getVivoAppData(ctx context.Context, pkgId string) (*gjson.Result, error) {
+	params := url.Values{}
+	params.Add("package_name", pkgId)
+	params.Add("frompage", "messageh5")
+
+	u := "https://h5-api.appstore.vivo.com.cn/detail?" + params.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	json := gjson.ParseBytes(bytes)
+	return &json, nil
+}
+
+// 判断是否在 vivo 应用商店上架
+func getVivoExist(json *gjson.Result) bool {
+	return json.Get("value.id").Exists()
+}
+
+// 获取名称
+func getVivoName(json *gjson.Result) string {
+	name := json.Get("value.title_zh")
+	return strings.TrimSpace(name.String())
+}
+
+// 获取开发者
+func getVivoSupplier(json *gjson.Result) string {
+	supplier := json.Get("value.developer")
+	return strings.TrimSpace(supplier.String())
+}
+
+// 获取评分
+func getVivoRate(json *gjson.Result) string {
+	rate := json.Get("value.score")
+	return strings.TrimSpace(rate.String())
+}
+
+// 获取下载量
+func getVivoDownloadCount(json *gjson.Result) string {
+	count := json.Get("value.download_count")
+	return strings.TrimSpace(count.String())
+}
+
+// 获取版本信息
+func getVivoLastVersion(json *gjson.Result) string {
+	version := json.Get("value.version_name")
+	return strings.TrimSpace(version.String())
+}
+
+// 获取版本更新时间
+func getVivoLastUpdate(json *gjson.Result) string {
+	update := json.Get("value.upload_time")
+	return strings.TrimSpace(update.String())
+}
+
+// 获取包大小，只有数字，单位是 KiB（apk 字节数除以 1024），不是十进制的 KB
+func getVivoPackageSize(json *gjson.Result) string {
+	size := json.Get("value.size")
+	return strings.TrimSpace(size.String())
+}
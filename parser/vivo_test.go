@@ -0,0 +1,93 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-24 15:12:09
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-24 15:12:09
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var VIVO_APP_ID = "com.ss.android.ugc.aweme"
+
+func TestGetVivoExist(t *testing.T) {
+	json, err := DefaultClient.getVivoAppData(context.Background(), VIVO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !getVivoExist(json) {
+		t.Error("exist 取错了")
+	}
+
+	json, err = DefaultClient.getVivoAppData(context.Background(), VIVO_APP_ID+"fake")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if getVivoExist(json) {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetVivoName(t *testing.T) {
+	json, err := DefaultClient.getVivoAppData(context.Background(), VIVO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getVivoName(json)
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetVivoLastVersion(t *testing.T) {
+	json, err := DefaultClient.getVivoAppData(context.Background(), VIVO_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getVivoLastVersion(json)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestParseVivoDataFake(t *testing.T) {
+	c, _ := NewClient(&Options{Transport: &fakeTransport{
+		status: 200,
+		body: `{"result": true, "value": {"id": 1, "title_zh": "抖音", "developer": "北京微播视界科技有限公司",
+			"score": 4.5, "download_count": 1234567890, "version_name": "28.5.0",
+			"upload_time": "2023-07-03 10:20:30", "size": 262144}}`,
+	}})
+
+	vivoData, err := c.ParseVivoData(context.Background(), VIVO_APP_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !vivoData.VivoExist || vivoData.VivoName != "抖音" {
+		t.Error("name 取错了")
+	}
+
+	if vivoData.VivoPackageBytes != 262144*1024 {
+		t.Error("size 取错了")
+	}
+
+	if vivoData.VivoLastUpdateTime.IsZero() {
+		t.Error("update 取错了")
+	}
+}
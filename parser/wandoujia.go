@@ -0,0 +1,231 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-27 16:32:47
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-27 16:32:47
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 豌豆荚
+type WDJData struct {
+	WDJExist         bool   `bson:"wdj_exist"`          // wdj 是否有
+	WDJPackageID     string `bson:"wdj_package_id"`     // wdj package id
+	WDJName          string `bson:"wdj_name"`           // wdj 名称
+	WDJSupplier      string `bson:"wdj_supplier"`       // wdj 开发者
+	WDJDownloadCount string `bson:"wdj_download_count"` // wdj 安装量
+	WDJLastVersion   string `bson:"wdj_last_version"`   // wdj 最新版本
+	WDJLastUpdate    string `bson:"wdj_last_update"`    // wdj 最新版本时间
+
+	WDJDownloadCountValue int64     `bson:"wdj_download_count_value"` // wdj 安装量，数字
+	WDJLastUpdateTime     time.Time `bson:"wdj_last_update_time"`     // wdj 最新版本时间
+}
+
+// 包名
+func (d *WDJData) PackageName() string {
+	return d.WDJPackageID
+}
+
+// 获取豌豆荚数据
+func ParseWDJData(pkgId string) (*WDJData, error) {
+	return ParseWDJDataContext(context.Background(), pkgId)
+}
+
+// 获取豌豆荚数据，ctx 取消或超时时中断请求
+func ParseWDJDataContext(ctx context.Context, pkgId string) (*WDJData, error) {
+	return DefaultClient.ParseWDJData(ctx, pkgId)
+}
+
+// 获取豌豆荚数据
+func (c *Client) ParseWDJData(ctx context.Context, pkgId string) (*WDJData, error) {
+	// 创建 wdj data 结构体
+	wdjData := new(WDJData)
+
+	if strings.TrimSpace(pkgId) == "" {
+		return wdjData, errors.New("pkgId 不能为空")
+	}
+
+	doc, err := c.getWDJDoc(ctx, pkgId)
+	if errors.Is(err, ErrNotFound) {
+		// 未收录的应用返回 404
+		return wdjData, nil
+	}
+	if err != nil {
+		return wdjData, err
+	}
+
+	wdjData.WDJExist = getWDJExist(doc)
+	if wdjData.WDJExist {
+		wdjData.WDJPackageID = pkgId
+		wdjData.WDJName = getWDJName(doc)
+		wdjData.WDJSupplier = getWDJSupplier(doc)
+		wdjData.WDJDownloadCount = getWDJDownloadCount(doc)
+		wdjData.WDJLastVersion = getWDJLastVersion(doc)
+		wdjData.WDJLastUpdate = getWDJLastUpdate(doc)
+		wdjData.WDJDownloadCountValue = normalizeCount(wdjData.WDJDownloadCount)
+		wdjData.WDJLastUpdateTime = normalizeLocalDate(wdjData.WDJLastUpdate)
+
+		if wdjData.WDJName == "" {
+			return wdjData, layoutError("名称")
+		}
+	}
+
+	return wdjData, nil
+}
+
+// 豌豆荚
+type wdjStore struct{}
+
+func (wdjStore) ID() string {
+	return StoreWDJ
+}
+
+func (wdjStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	wdjData, err := c.ParseWDJData(ctx, id)
+	if err != nil {
+		return wdjData, err
+	}
+
+	if !wdjData.WDJExist {
+		return wdjData, ErrNotFound
+	}
+
+	return wdjData, nil
+}
+
+func (wdjStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	doc, err := c.getWDJSearchDoc(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return getWDJSearchID(doc, name), nil
+}
+
+func (wdjStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	wdjData, err := c.ParseWDJData(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return wdjData.WDJExist, nil
+}
+
+func (wdjStore) UsesPackageName() bool {
+	return true
+}
+
+func (c *Client) getWDJDoc(ctx context.Context, id string) (*goquery.Document, error) {
+	u := "https://www.wandoujia.com/apps/" + url.PathEscape(id)
+	return c.getWDJPage(ctx, u)
+}
+
+// 豌豆荚搜索结果页面的 doc
+func (c *Client) getWDJSearchDoc(ctx context.Context, name string) (*goquery.Document, error) {
+	params := url.Values{}
+	params.Add("key", name)
+
+	u := "https://www.wandoujia.com/search?" + params.Encode()
+	return c.getWDJPage(ctx, u)
+}
+
+func (c *Client) getWDJPage(ctx context.Context, u string) (*goquery.Document, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", UA)
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 从搜索结果中获取包名，只取第一个结果，名称不匹配时返回空
+func getWDJSearchID(doc *goquery.Document, name string) string {
+	node := doc.Find("#j-search-list .search-item").First()
+	findName := strings.TrimSpace(node.Find(".app-title-h2 a").Text())
+
+	// 判断名称是否匹配
+	if findName == "" || !strings.Contains(name, findName) {
+		return ""
+	}
+
+	return strings.TrimSpace(node.AttrOr("data-pn", ""))
+}
+
+// 判断是否在豌豆荚上架
+func getWDJExist(doc *goquery.Document) bool {
+	node := doc.Find(".app-info .app-name")
+	return node.Length() > 0
+}
+
+// 获取名称
+func getWDJName(doc *goquery.Document) string {
+	node := doc.Find(".app-info .app-name .title").First()
+	return strings.TrimSpace(node.Text())
+}
+
+// 获取安装量，例：1.2亿人安装
+func getWDJDownloadCount(doc *goquery.Document) string {
+	node := doc.Find(".num-list .install i").First()
+	return strings.TrimSpace(node.Text())
+}
+
+// 获取开发者
+func getWDJSupplier(doc *goquery.Document) string {
+	return getWDJAttr(doc, "开发者")
+}
+
+// 获取最新版本号
+func getWDJLastVersion(doc *goquery.Document) string {
+	return getWDJAttr(doc, "版本")
+}
+
+// 获取最新更新时间，例：2023年07月20日
+func getWDJLastUpdate(doc *goquery.Document) string {
+	return getWDJAttr(doc, "更新")
+}
+
+// 获取详细信息中的一项，例：<dt>版本</dt><dd>26.8.0</dd>
+func getWDJAttr(doc *goquery.Document, label string) string {
+	value := ""
+
+	doc.Find(".infos-list dt").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if strings.TrimSpace(s.Text()) != label {
+			return true
+		}
+
+		value = s.NextFiltered("dd").Text()
+		return false
+	})
+
+	return strings.TrimSpace(value)
+}
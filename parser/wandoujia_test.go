@@ -0,0 +1,71 @@
+/*
+ * @Author: easonchiu
+ * @Date: 2023-07-27 17:50:09
+ * @LastEditors: easonchiu
+ * @LastEditTime: 2023-07-27 17:50:09
+ * @Description:
+ */
+package parser
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var WDJ_APP_ID = "com.ss.android.ugc.aweme"
+
+func TestGetWDJExist(t *testing.T) {
+	doc, err := DefaultClient.getWDJDoc(context.Background(), WDJ_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !getWDJExist(doc) {
+		t.Error("exist 取错了")
+	}
+}
+
+func TestGetWDJName(t *testing.T) {
+	doc, err := DefaultClient.getWDJDoc(context.Background(), WDJ_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	name := getWDJName(doc)
+
+	if name == "" {
+		t.Error("name 为空")
+	}
+}
+
+func TestGetWDJLastVersion(t *testing.T) {
+	doc, err := DefaultClient.getWDJDoc(context.Background(), WDJ_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	version := getWDJLastVersion(doc)
+
+	reg := regexp.MustCompile("^[0-9.]+$")
+	if !reg.MatchString(version) {
+		t.Error("version 取错了")
+	}
+}
+
+func TestGetWDJDownloadCount(t *testing.T) {
+	doc, err := DefaultClient.getWDJDoc(context.Background(), WDJ_APP_ID)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	count := getWDJDownloadCount(doc)
+
+	if normalizeCount(count) == 0 {
+		t.Error("count 取错了")
+	}
+}
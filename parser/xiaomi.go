@@ -8,26 +8,50 @@
 package parser
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 // 小米市场
 type MIData struct {
-	MIExist       bool   `bson:"mi_exist"`        // mi 是否有
-	MIPackageID   string `bson:"mi_package_id"`   // mi package id
-	MIName        string `bson:"mi_name"`         // mi 名称
-	MIRateCount   string `bson:"mi_rate_count"`   // mi 评价数
-	MILastVersion string `bson:"mi_last_version"` // mi 最新版本
-	MILastUpdate  string `bson:"mi_last_update"`  // mi 最新版本时间
+	MIExist       bool         `bson:"mi_exist"`        // mi 是否有
+	MIPackageID   string       `bson:"mi_package_id"`   // mi package id
+	MIName        string       `bson:"mi_name"`         // mi 名称
+	MIRateCount   string       `bson:"mi_rate_count"`   // mi 评价数
+	MILastVersion string       `bson:"mi_last_version"` // mi 最新版本
+	MILastUpdate  string       `bson:"mi_last_update"`  // mi 最新版本时间
+	MIMedia       Media        `bson:"mi_media"`        // mi 截图
+	MIPermissions []Permission `bson:"mi_permissions"`  // mi 权限列表
+
+	MIRateCountValue  int64           `bson:"mi_rate_count_value"` // mi 评价数，数字
+	MILastUpdateTime  time.Time       `bson:"mi_last_update_time"` // mi 最新版本时间
+	MIRatingHistogram RatingHistogram `bson:"mi_rating_histogram"` // mi 各星级的评分数
+}
+
+// 包名
+func (d *MIData) PackageName() string {
+	return d.MIPackageID
 }
 
 // 获取小米市场数据
 func ParseMIData(pkgId string) (*MIData, error) {
+	return ParseMIDataContext(context.Background(), pkgId)
+}
+
+// 获取小米市场数据，ctx 取消或超时时中断请求
+func ParseMIDataContext(ctx context.Context, pkgId string) (*MIData, error) {
+	return DefaultClient.ParseMIData(ctx, pkgId)
+}
+
+// 获取小米市场数据
+func (c *Client) ParseMIData(ctx context.Context, pkgId string) (*MIData, error) {
 	// 创建 mi data 结构体
 	miData := new(MIData)
 
@@ -35,7 +59,7 @@ func ParseMIData(pkgId string) (*MIData, error) {
 		return miData, errors.New("pkgId 不能为空")
 	}
 
-	doc, err := getMIDoc(pkgId)
+	doc, err := c.getMIDoc(ctx, pkgId)
 	if err != nil {
 		return miData, err
 	}
@@ -45,31 +69,114 @@ func ParseMIData(pkgId string) (*MIData, error) {
 		miData.MIPackageID = pkgId
 		miData.MIName = getMIName(doc)
 		miData.MIRateCount = getMIRateCount(doc)
+		miData.MIRatingHistogram = getMIRatingHistogram(doc)
 		miData.MILastVersion = getMILastVersion(doc)
 		miData.MILastUpdate = getMILastUpdate(doc)
+		miData.MIMedia = getMIMedia(doc)
+		miData.MIPermissions = getMIPermissions(doc)
+		miData.MIRateCountValue = normalizeCount(miData.MIRateCount)
+		miData.MILastUpdateTime = normalizeLocalDate(miData.MILastUpdate)
+
+		if miData.MIName == "" {
+			return miData, layoutError("名称")
+		}
+	}
+
+	return miData, nil
+}
+
+// 小米市场
+type miStore struct{}
+
+func (miStore) ID() string {
+	return StoreMI
+}
+
+func (miStore) Lookup(ctx context.Context, c *Client, id string) (interface{}, error) {
+	miData, err := c.ParseMIData(ctx, id)
+	if err != nil {
+		return miData, err
+	}
+
+	if !miData.MIExist {
+		return miData, ErrNotFound
 	}
 
 	return miData, nil
 }
 
-func getMIDoc(id string) (*goquery.Document, error) {
-	url := "https://app.mi.com/details?id=" + id + "&ref=search"
-	client := &http.Client{}
+func (miStore) Search(ctx context.Context, c *Client, name string) (string, error) {
+	doc, err := c.getMISearchDoc(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return getMISearchID(doc, name), nil
+}
+
+func (miStore) UsesPackageName() bool {
+	return true
+}
+
+func (miStore) Exists(ctx context.Context, c *Client, id string) (bool, error) {
+	doc, err := c.getMIDoc(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return getMIExist(doc), nil
+}
+
+func (c *Client) getMIDoc(ctx context.Context, id string) (*goquery.Document, error) {
+	u := "https://app.mi.com/details?id=" + id + "&ref=search"
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	// Load the HTML document
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// 小米市场搜索结果页面的 doc
+func (c *Client) getMISearchDoc(ctx context.Context, name string) (*goquery.Document, error) {
+	params := url.Values{}
+	params.Add("keywords", name)
+	params.Add("typeall", "phone")
 
-	request, err := http.NewRequest("GET", url, nil)
+	u := "https://app.mi.com/searchAll?" + params.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(request)
+	resp, err := c.HTTPClient().Do(request)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("返回状态错误 %v", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Load the HTML document
@@ -81,6 +188,25 @@ func getMIDoc(id string) (*goquery.Document, error) {
 	return doc, nil
 }
 
+// 从搜索结果中获取包名，只取第一个结果，名称不匹配时返回空
+func getMISearchID(doc *goquery.Document, name string) string {
+	node := doc.Find(".applist li h5 a").First()
+	findName := strings.TrimSpace(node.Text())
+
+	// 判断名称是否匹配
+	if findName == "" || !strings.Contains(name, findName) {
+		return ""
+	}
+
+	// 例：/details?id=com.ss.android.ugc.aweme
+	href, err := url.Parse(node.AttrOr("href", ""))
+	if err != nil {
+		return ""
+	}
+
+	return href.Query().Get("id")
+}
+
 // 判断是否在小米市场上架
 func getMIExist(doc *goquery.Document) bool {
 	node := doc.Find(".bigimg-scroll-title")
@@ -104,6 +230,22 @@ func getMIRateCount(doc *goquery.Document) string {
 	return strings.TrimSpace(txt)
 }
 
+// 获取各星级的评分数，例：<li data-star="5"><span class="num">1200</span></li>，页面上没有评分分布时为零值
+func getMIRatingHistogram(doc *goquery.Document) RatingHistogram {
+	var h RatingHistogram
+
+	doc.Find(".comment-score-list li[data-star]").Each(func(i int, s *goquery.Selection) {
+		star, err := strconv.Atoi(s.AttrOr("data-star", ""))
+		if err != nil {
+			return
+		}
+
+		h.set(star, normalizeCount(s.Find(".num").Text()))
+	})
+
+	return h
+}
+
 // 获取最新版本号
 func getMILastVersion(doc *goquery.Document) string {
 	node := doc.Find(".main .container")
@@ -151,3 +293,44 @@ func getMILastUpdate(doc *goquery.Document) string {
 
 	return ""
 }
+
+// 获取截图，小米市场网页版没有预览视频
+func getMIMedia(doc *goquery.Document) Media {
+	media := Media{
+		Screenshots: make([]*Screenshot, 0),
+		Videos:      make([]*Video, 0),
+	}
+
+	doc.Find("#J_thumbnail_wrap img, .img-list img").Each(func(i int, s *goquery.Selection) {
+		u := strings.TrimSpace(s.AttrOr("src", ""))
+		if u != "" {
+			media.Screenshots = append(media.Screenshots, &Screenshot{URL: u})
+		}
+	})
+
+	return media
+}
+
+// 获取权限列表，页面上每行是一个权限，例：• 相机：拍摄照片和视频，没有冒号时整行作为名称
+func getMIPermissions(doc *goquery.Document) []Permission {
+	list := make([]Permission, 0)
+
+	doc.Find(".second-ul li").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		text = strings.TrimSpace(strings.TrimLeft(text, "•·"))
+		if text == "" {
+			return
+		}
+
+		p := Permission{Name: text}
+		if idx := strings.IndexAny(text, "：:"); idx > 0 {
+			p.Name = strings.TrimSpace(text[:idx])
+			p.Description = strings.TrimLeft(text[idx:], "：:")
+			p.Description = strings.TrimSpace(p.Description)
+		}
+
+		list = append(list, p)
+	})
+
+	return list
+}
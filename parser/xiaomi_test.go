@@ -8,14 +8,18 @@
 package parser
 
 import (
+	"context"
 	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 var MI_APP_ID = "com.ss.android.ugc.aweme"
 
 func TestGetMiRateCount(t *testing.T) {
-	doc, err := getMIDoc(MI_APP_ID)
+	doc, err := DefaultClient.getMIDoc(context.Background(), MI_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -30,7 +34,7 @@ func TestGetMiRateCount(t *testing.T) {
 }
 
 func TestGetMiExist(t *testing.T) {
-	doc, err := getMIDoc(MI_APP_ID)
+	doc, err := DefaultClient.getMIDoc(context.Background(), MI_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -41,7 +45,7 @@ func TestGetMiExist(t *testing.T) {
 		t.Error("exist 取错了")
 	}
 
-	doc, err = getMIDoc(MI_APP_ID + "fake")
+	doc, err = DefaultClient.getMIDoc(context.Background(), MI_APP_ID+"fake")
 
 	if err != nil {
 		t.Error(err)
@@ -54,7 +58,7 @@ func TestGetMiExist(t *testing.T) {
 }
 
 func TestGetMiName(t *testing.T) {
-	doc, err := getMIDoc(MI_APP_ID)
+	doc, err := DefaultClient.getMIDoc(context.Background(), MI_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -68,7 +72,7 @@ func TestGetMiName(t *testing.T) {
 }
 
 func TestGetMiLastVersion(t *testing.T) {
-	doc, err := getMIDoc(MI_APP_ID)
+	doc, err := DefaultClient.getMIDoc(context.Background(), MI_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -83,7 +87,7 @@ func TestGetMiLastVersion(t *testing.T) {
 }
 
 func TestGetMiLastUpdate(t *testing.T) {
-	doc, err := getMIDoc(MI_APP_ID)
+	doc, err := DefaultClient.getMIDoc(context.Background(), MI_APP_ID)
 
 	if err != nil {
 		t.Error(err)
@@ -96,3 +100,34 @@ func TestGetMiLastUpdate(t *testing.T) {
 		t.Error("update 取错了")
 	}
 }
+
+func TestGetMISearchID(t *testing.T) {
+	doc, err := DefaultClient.getMISearchDoc(context.Background(), "抖音")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	id := getMISearchID(doc, "抖音")
+
+	if id != MI_APP_ID {
+		t.Error("id 取错了")
+	}
+}
+
+func TestGetMIRatingHistogram(t *testing.T) {
+	html := `<ul class="comment-score-list">
+		<li data-star="5"><span class="num">1.2万</span></li>
+		<li data-star="1"><span class="num">300</span></li>
+	</ul>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := getMIRatingHistogram(doc)
+	if h.Star5 != 12000 || h.Star1 != 300 || h.Star3 != 0 {
+		t.Error("histogram 取错了", h)
+	}
+}